@@ -12,6 +12,7 @@ import (
 
 	"telegram-discord/bot/discord"
 	"telegram-discord/bot/telegram"
+	"telegram-discord/lib/parser/parserv5"
 
 	"github.com/charmbracelet/log"
 )
@@ -20,6 +21,14 @@ type Bot struct {
 	Discord  *discord.Bot
 	Telegram *telegram.Bot
 	Bots     []Bots
+	Options  Options
+	// Parser configures how Discord messages are converted for Telegram, as set up from Options.
+	Parser parserv5.Options
+
+	// pollTimers holds the timers closing native polls at their Discord expiry, keyed by Discord message ID.
+	pollTimers map[string]*time.Timer
+	// pollsMu guards pollTimers and serializes closing polls.
+	pollsMu sync.Mutex
 }
 
 type Bots interface {
@@ -55,6 +64,8 @@ type Config struct {
 	TelegramChannelID string
 	TelegramThreadID  string
 	TelegramLogger    io.Writer
+
+	Options Options
 }
 
 func New(config Config) (*Bot, error) {
@@ -84,6 +95,15 @@ func New(config Config) (*Bot, error) {
 		return nil, fmt.Errorf("error creating Telegram bot: %w", err)
 	}
 
+	options := config.Options.withDefaults()
+	mapping, err := parserv5.LoadEmojiMapping(options.EmojiMapping)
+	if err != nil {
+		return nil, err
+	}
+	tgBot.Render = options.Renderer.Render
+	tgBot.Fallback = parserv5.RendererEntities.Render
+	tgBot.Samples = options.MarkupSamples
+
 	return &Bot{
 		Discord:  discordBot,
 		Telegram: tgBot,
@@ -91,6 +111,13 @@ func New(config Config) (*Bot, error) {
 			discordBot,
 			tgBot,
 		},
+		Options: options,
+		Parser: parserv5.Options{
+			Emoji:         parserv5.EmojiOptions{Mode: options.EmojiMode, Mapping: mapping},
+			Files:         options.FileServer,
+			AltTextFormat: options.AltTextFormat,
+			Logger:        discordBot.Logger(),
+		},
 	}, nil
 }
 
@@ -149,6 +176,7 @@ func (b *Bot) Start() error {
 	}
 
 	b.registerMainHandler()
+	b.schedulePolls()
 	b.Discord.Logger().Info("Message mirroring bot is running")
 	return nil
 }
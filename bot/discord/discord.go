@@ -28,10 +28,55 @@ type Bot struct {
 type Tracked struct {
 	Discord  *discordgo.Message `json:"discord,omitempty"`
 	Telegram *telebot.Message   `json:"telegram,omitempty"`
+	// Album holds the remaining Telegram messages when the Discord message was sent as a media group.
+	Album []*telebot.Message `json:"album,omitempty"`
+	// Parts holds the Telegram text messages continuing the first one, for text that did not fit in it.
+	Parts []*telebot.Message `json:"parts,omitempty"`
+	// Event is set instead of Discord when the Telegram message is the card of a scheduled event.
+	Event *discordgo.GuildScheduledEvent `json:"event,omitempty"`
 
 	Expiry time.Time `json:"expiry"`
 }
 
+// storedPoll shadows telebot.Poll.Type, which marshals as a keyboard button object
+// and cannot be decoded back into a telebot.Poll.
+type storedPoll struct {
+	telebot.Poll
+	Type string `json:"type"`
+}
+
+type storedTracked struct {
+	trackedAlias
+	Poll *storedPoll `json:"poll,omitempty"`
+}
+
+type trackedAlias Tracked
+
+func (t Tracked) MarshalJSON() ([]byte, error) {
+	stored := storedTracked{trackedAlias: trackedAlias(t)}
+	if t.Telegram != nil && t.Telegram.Poll != nil {
+		telegram := *t.Telegram
+		stored.Poll = &storedPoll{Poll: *telegram.Poll, Type: string(telegram.Poll.Type)}
+		telegram.Poll = nil
+		stored.Telegram = &telegram
+	}
+	return json.Marshal(stored)
+}
+
+func (t *Tracked) UnmarshalJSON(data []byte) error {
+	var stored storedTracked
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	*t = Tracked(stored.trackedAlias)
+	if stored.Poll != nil && t.Telegram != nil {
+		poll := stored.Poll.Poll
+		poll.Type = telebot.PollType(stored.Poll.Type)
+		t.Telegram.Poll = &poll
+	}
+	return nil
+}
+
 func New(token string, discordChannelID string, output io.Writer) (*Bot, error) {
 	dg, err := discordgo.New("Bot " + token)
 	if err != nil {
@@ -142,9 +187,6 @@ func (b *Bot) save() error {
 	b.Clean()
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
-	for _, v := range b.tracked {
-		v.Telegram.Poll = nil // do not store polls because telebot.Message.Poll.Type does not marshal properly
-	}
 	if err := enc.Encode(b.tracked); err != nil {
 		return fmt.Errorf("error encoding tracked messages: %w", err)
 	}
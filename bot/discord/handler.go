@@ -89,12 +89,50 @@ func (b *Bot) Handlers() {
 	})
 }
 
-func (b *Bot) Set(discord *discordgo.Message, telegram *telebot.Message) {
-	if discord == nil || telegram == nil {
+// TrackedExpiry is how long a forwarded message is tracked for edits and deletes.
+// It outlives Telegram's deletion window so that older messages can still be redacted.
+const TrackedExpiry = 30 * 24 * time.Hour
+
+// Set tracks the Telegram messages a Discord message was forwarded as.
+// The first message is the one replied to and edited, any further messages are the rest of its album.
+func (b *Bot) Set(discord *discordgo.Message, telegram ...*telebot.Message) {
+	if discord == nil || len(telegram) == 0 || telegram[0] == nil {
 		return
 	}
+	// Store a copy, the state cache merges later edits into the original message in place,
+	// which would hide what was forwarded before the edit.
+	forwarded := *discord
 	b.mutex.Lock()
-	b.tracked[discord.ID] = Tracked{discord, telegram, time.Now().UTC().Add(48 * time.Hour)}
+	b.tracked[discord.ID] = Tracked{Discord: &forwarded, Telegram: telegram[0], Album: telegram[1:], Expiry: time.Now().UTC().Add(TrackedExpiry)}
+	b.mutex.Unlock()
+}
+
+// SetEvent tracks the Telegram card of a scheduled event until TrackedExpiry after the event ends.
+func (b *Bot) SetEvent(event *discordgo.GuildScheduledEvent, telegram *telebot.Message) {
+	if event == nil || telegram == nil {
+		return
+	}
+	end := event.ScheduledStartTime
+	if event.ScheduledEndTime != nil {
+		end = *event.ScheduledEndTime
+	}
+	expiry := time.Now().UTC().Add(TrackedExpiry)
+	if end.Add(TrackedExpiry).After(expiry) {
+		expiry = end.Add(TrackedExpiry).UTC()
+	}
+	scheduled := *event
+	b.mutex.Lock()
+	b.tracked[event.ID] = Tracked{Telegram: telegram, Event: &scheduled, Expiry: expiry}
+	b.mutex.Unlock()
+}
+
+// SetParts tracks the Telegram messages continuing the tracked message id, replacing any previous ones.
+func (b *Bot) SetParts(id string, parts []*telebot.Message) {
+	b.mutex.Lock()
+	if tracked, ok := b.tracked[id]; ok {
+		tracked.Parts = parts
+		b.tracked[id] = tracked
+	}
 	b.mutex.Unlock()
 }
 
@@ -105,6 +143,19 @@ func (b *Bot) Get(id string) (Tracked, bool) {
 	return tracked, ok
 }
 
+// Polls returns the tracked messages that were forwarded as native Telegram polls.
+func (b *Bot) Polls() []Tracked {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var polls []Tracked
+	for _, tracked := range b.tracked {
+		if tracked.Telegram.Poll != nil {
+			polls = append(polls, tracked)
+		}
+	}
+	return polls
+}
+
 func (b *Bot) Unset(id string) {
 	b.mutex.Lock()
 	delete(b.tracked, id)
@@ -125,6 +176,12 @@ func (b *Bot) Clean() {
 	b.mutex.Unlock()
 }
 
+// Messages returns every Telegram message the tracked message was forwarded as, including its album and parts.
+func (t *Tracked) Messages() []*telebot.Message {
+	messages := append([]*telebot.Message{t.Telegram}, t.Album...)
+	return append(messages, t.Parts...)
+}
+
 func (t *Tracked) Expired() bool {
 	return time.Now().UTC().After(t.Expiry)
 }
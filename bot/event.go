@@ -0,0 +1,179 @@
+package bot
+
+import (
+	"telegram-discord/bot/discord"
+	"telegram-discord/lib"
+	"telegram-discord/lib/parser/parserv5"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+func (b *Bot) eventCreateHandler(s *discordgo.Session, e *discordgo.GuildScheduledEventCreate) error {
+	if !b.eventInGuild(s, e.GuildScheduledEvent) {
+		return nil
+	}
+	return b.postEvent(s, e.GuildScheduledEvent)
+}
+
+func (b *Bot) eventUpdateHandler(s *discordgo.Session, e *discordgo.GuildScheduledEventUpdate) error {
+	if !b.eventInGuild(s, e.GuildScheduledEvent) {
+		return nil
+	}
+	reference, ok := b.Discord.Get(e.ID)
+	if !ok || reference.Event == nil {
+		b.Discord.Logger().Debug(
+			"Scheduled event was updated but not tracked, posting it",
+			"event_id", e.ID,
+			"name", e.Name,
+		)
+		return b.postEvent(s, e.GuildScheduledEvent)
+	}
+
+	if err := b.editEvent(s, reference, e.GuildScheduledEvent); err != nil {
+		return err
+	}
+	if reference.Event.Status != discordgo.GuildScheduledEventStatusActive && e.Status == discordgo.GuildScheduledEventStatusActive {
+		return b.announceEvent(s, reference, e.GuildScheduledEvent)
+	}
+	return nil
+}
+
+func (b *Bot) eventDeleteHandler(s *discordgo.Session, e *discordgo.GuildScheduledEventDelete) error {
+	if !b.eventInGuild(s, e.GuildScheduledEvent) {
+		return nil
+	}
+	reference, ok := b.Discord.Get(e.ID)
+	if !ok || reference.Event == nil {
+		b.Discord.Logger().Debug(
+			"Scheduled event was deleted but not tracked",
+			"event_id", e.ID,
+			"name", e.Name,
+		)
+		return nil
+	}
+	cancelled := *e.GuildScheduledEvent
+	cancelled.Status = discordgo.GuildScheduledEventStatusCanceled
+	if err := b.editEvent(s, reference, &cancelled); err != nil {
+		return err
+	}
+	b.Discord.Unset(e.ID)
+	return nil
+}
+
+// eventInGuild reports whether event belongs to the guild of the registered Discord channel.
+func (b *Bot) eventInGuild(s *discordgo.Session, event *discordgo.GuildScheduledEvent) bool {
+	if b.Discord.Channel == "" || b.Telegram.Channel == 0 {
+		return false
+	}
+	channel, err := s.State.Channel(b.Discord.Channel)
+	if err != nil {
+		b.Discord.Logger().Debug(
+			"Skipping scheduled event - registered channel not cached",
+			"event_id", event.ID,
+			"channel", lib.ChannelNameID(s, b.Discord.Channel),
+		)
+		return false
+	}
+	return channel.GuildID == event.GuildID
+}
+
+// postEvent sends the card of event to Telegram and tracks it for later updates.
+func (b *Bot) postEvent(s *discordgo.Session, event *discordgo.GuildScheduledEvent) error {
+	toSend, err := parserv5.EventSendable(s, event, b.Parser)
+	if err != nil {
+		b.Discord.Logger().Error(
+			"Failed to render scheduled event",
+			"error", err,
+			"event_id", event.ID,
+			"name", event.Name,
+		)
+		return err
+	}
+	reference, err := b.Telegram.Send(toSend, b.eventOptions(event))
+	if err != nil {
+		b.Discord.Logger().Error(
+			"Failed to post scheduled event to Telegram",
+			"error", err,
+			"event_id", event.ID,
+			"name", event.Name,
+		)
+		return err
+	}
+	b.Discord.SetEvent(event, reference)
+	b.Discord.Logger().Info(
+		"Posted scheduled event to Telegram",
+		"event_id", event.ID,
+		"name", event.Name,
+	)
+	return nil
+}
+
+// editEvent updates the tracked card of event, resending it when it changes between a photo and text,
+// such as when the cover image was added or removed.
+func (b *Bot) editEvent(s *discordgo.Session, reference discord.Tracked, event *discordgo.GuildScheduledEvent) error {
+	toSend, err := parserv5.EventSendable(s, event, b.Parser)
+	if err != nil {
+		return err
+	}
+	options := b.eventOptions(event)
+
+	var edited *telebot.Message
+	_, media := toSend.(telebot.Media)
+	switch {
+	case media != (reference.Telegram.Media() != nil):
+		edited, err = b.Telegram.Send(toSend, options)
+		if err == nil && b.Telegram.Delete(reference.Telegram) != nil {
+			_, _ = b.Telegram.Redact(reference.Telegram, b.Options.RedactPlaceholder)
+		}
+	case !media:
+		edited, err = b.Telegram.Edit(reference.Telegram, toSend, options)
+	case reference.Event.Image == event.Image:
+		edited, err = b.Telegram.EditCaption(reference.Telegram, parserv5.EventCard(s, event, b.Parser), options)
+	default:
+		edited, err = b.Telegram.Edit(reference.Telegram, toSend, options)
+	}
+	if err != nil {
+		b.Discord.Logger().Error(
+			"Failed to update scheduled event on Telegram",
+			"error", err,
+			"event_id", event.ID,
+			"name", event.Name,
+		)
+		return err
+	}
+	b.Discord.SetEvent(event, edited)
+	b.Discord.Logger().Info(
+		"Updated scheduled event on Telegram",
+		"event_id", event.ID,
+		"name", event.Name,
+		"status", event.Status,
+	)
+	return nil
+}
+
+// announceEvent replies to the card of event with a notice that it is starting.
+func (b *Bot) announceEvent(s *discordgo.Session, reference discord.Tracked, event *discordgo.GuildScheduledEvent) error {
+	options := b.eventOptions(event)
+	options.ReplyTo = reference.Telegram
+	_, err := b.Telegram.Send(parserv5.EventStarting(s, event, b.Parser), options)
+	if err != nil {
+		b.Discord.Logger().Error(
+			"Failed to announce scheduled event start",
+			"error", err,
+			"event_id", event.ID,
+			"name", event.Name,
+		)
+		return err
+	}
+	return nil
+}
+
+func (b *Bot) eventOptions(event *discordgo.GuildScheduledEvent) *telebot.SendOptions {
+	return &telebot.SendOptions{
+		ThreadID: b.Telegram.ThreadID,
+		ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{
+			{Text: parserv5.JumpText, URL: parserv5.EventURL(event)},
+		}}},
+	}
+}
@@ -3,7 +3,11 @@ package bot
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
+	"telegram-discord/bot/discord"
+	"telegram-discord/bot/telegram"
 	"telegram-discord/lib"
 	"telegram-discord/lib/parser/parserv5"
 
@@ -12,6 +16,8 @@ import (
 )
 
 func (b *Bot) registerMainHandler() {
+	b.Discord.Session.AddHandler(lib.StoreAttachmentDescriptions)
+
 	b.Discord.Session.AddHandler(Chain(
 		b.mainHandler,
 		SkipperMiddleware(b.Discord.Logger(), OnlyBots),
@@ -24,10 +30,30 @@ func (b *Bot) registerMainHandler() {
 		RetryMiddleware[*discordgo.MessageDelete](b.Discord.Logger(), 3),
 	))
 
+	b.Discord.Session.AddHandler(Chain(
+		b.bulkDeleteHandler,
+		RetryMiddleware[*discordgo.MessageDeleteBulk](b.Discord.Logger(), 3),
+	))
+
 	b.Discord.Session.AddHandler(Chain(
 		b.messageUpdateHandler,
 		RetryMiddleware[*discordgo.MessageUpdate](b.Discord.Logger(), 3, telebot.ErrMessageNotModified, telebot.ErrSameMessageContent),
 	))
+
+	b.Discord.Session.AddHandler(Chain(
+		b.eventCreateHandler,
+		RetryMiddleware[*discordgo.GuildScheduledEventCreate](b.Discord.Logger(), 3),
+	))
+
+	b.Discord.Session.AddHandler(Chain(
+		b.eventUpdateHandler,
+		RetryMiddleware[*discordgo.GuildScheduledEventUpdate](b.Discord.Logger(), 3, telebot.ErrMessageNotModified, telebot.ErrSameMessageContent),
+	))
+
+	b.Discord.Session.AddHandler(Chain(
+		b.eventDeleteHandler,
+		RetryMiddleware[*discordgo.GuildScheduledEventDelete](b.Discord.Logger(), 3, telebot.ErrMessageNotModified, telebot.ErrSameMessageContent),
+	))
 }
 
 func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) error {
@@ -69,8 +95,34 @@ func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) erro
 		return nil
 	}
 
+	if parserv5.IsSystem(m.Message) && !b.Options.SystemMessages[m.Type] {
+		b.Discord.Logger().Debug(
+			"Skipping message - system message type disabled",
+			"message_id", m.ID,
+			"type", m.Type,
+			"channel", lib.ChannelNameID(s, m.ChannelID),
+			"author", lib.GetUsername(m),
+		)
+		return nil
+	}
+
+	flags := parserv5.TranslateFlags(m.Message)
+	if flags.Skip() {
+		b.Discord.Logger().Debug(
+			"Skipping message - ephemeral or loading",
+			"message_id", m.ID,
+			"channel", lib.ChannelNameID(s, m.ChannelID),
+			"author", lib.GetUsername(m),
+		)
+		return nil
+	}
+
 	message := m.Message
-	var toReply *telebot.Message
+	var (
+		toReply *telebot.Message
+		// prefix is the quote of an untracked reply target or the attribution of a forward.
+		prefix parserv5.Text
+	)
 	if m.MessageReference != nil {
 		switch m.MessageReference.Type {
 		case discordgo.MessageReferenceTypeDefault:
@@ -78,17 +130,19 @@ func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) erro
 			if ok {
 				toReply = reference.Telegram
 			} else {
-				b.Discord.Logger().Warn("Could not find message reference for reply",
+				b.Discord.Logger().Warn("Could not find message reference for reply, quoting it instead",
 					"message_id", message.ID,
 					"reference_id", m.MessageReference.MessageID,
 				)
+				prefix = b.replyQuote(s, m.Message)
 			}
 		case discordgo.MessageReferenceTypeForward:
-			retrieve, err := lib.GetReference(b.Discord.Logger(), s, m)
+			forward, err := lib.GetForward(b.Discord.Logger(), s, m.Message)
 			if err != nil {
 				return err
 			}
-			message = retrieve
+			message = forward
+			prefix = parserv5.Parser(s, message, b.Parser)(lib.ForwardAttribution(s, m.Message))
 		}
 	}
 
@@ -98,7 +152,22 @@ func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) erro
 		"channel", lib.ChannelNameID(s, message.ChannelID),
 		"author", lib.GetUsername(message),
 	)
-	toSend, err := parserv5.Sendable(s, message, parserv5.Parser(s, message))
+	var (
+		toSend any
+		err    error
+	)
+	poll := parserv5.NativePoll(message.Poll)
+	if !b.Options.NativePolls {
+		poll = nil
+	}
+	if text, ok := parserv5.SystemMessage(message); ok {
+		toSend = parserv5.Parser(s, message, b.Parser)(text)
+	} else if poll != nil {
+		toSend = poll
+	} else {
+		descriptions := lib.AttachmentDescriptions(b.Discord.Logger(), s, m.Message, true)
+		toSend, err = parserv5.Sendable(s, message, descriptions, b.Parser)
+	}
 	if err != nil {
 		b.Discord.Logger().Error(
 			"Failed to process message",
@@ -119,6 +188,8 @@ func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) erro
 		return nil
 	}
 
+	toSend = parserv5.WithPrefix(toSend, prefix)
+
 	b.Discord.Logger().Info(
 		"Forwarding message to Telegram",
 		"message_id", message.ID,
@@ -128,21 +199,15 @@ func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) erro
 	)
 
 	options := &telebot.SendOptions{
-		ReplyTo:   toReply,
-		ParseMode: telebot.ModeMarkdownV2,
-		ThreadID:  b.Telegram.ThreadID,
-	}
-	if m.Poll != nil {
-		options.ReplyMarkup = &telebot.ReplyMarkup{
-			InlineKeyboard: [][]telebot.InlineButton{
-				{{
-					Text: "VOTE HERE (Discord)",
-					URL:  fmt.Sprintf("https://discord.com/channels/%s/%s/%s", m.GuildID, m.ChannelID, m.ID),
-				}},
-			},
-		}
+		ReplyTo:  toReply,
+		ThreadID: b.Telegram.ThreadID,
+	}
+	if poll == nil {
+		options.ReplyMarkup = b.replyMarkup(message, lib.MessageURL(m.Message))
 	}
-	reference, err := b.Telegram.Send(toSend, options)
+	flags.Apply(options)
+	toSend, parts := splitOverflow(toSend)
+	sent, err := b.send(toSend, options)
 	if err != nil {
 		b.Discord.Logger().Error(
 			"Failed to forward message to Telegram",
@@ -154,7 +219,16 @@ func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) erro
 		)
 		return err
 	}
-	b.Discord.Set(message, reference)
+	b.Discord.Set(message, sent...)
+	if len(parts) > 0 {
+		// The media was already sent, so failed parts are only logged rather than retried with it.
+		forwarded, _ := b.reconcileParts(sent[0], nil, parts, options)
+		b.Discord.SetParts(message.ID, forwarded)
+	}
+	parserv5.RememberSticker(message, sent[0])
+	if poll != nil {
+		b.schedulePollClose(message.ID, message.Poll.Expiry)
+	}
 	b.Discord.Logger().Info(
 		"Successfully forwarded message to Telegram",
 		"message_id", message.ID,
@@ -165,6 +239,19 @@ func (b *Bot) mainHandler(s *discordgo.Session, m *discordgo.MessageCreate) erro
 	return nil
 }
 
+// replyQuote renders an excerpt of the message m replies to, for replies whose target is not tracked.
+func (b *Bot) replyQuote(s *discordgo.Session, m *discordgo.Message) parserv5.Text {
+	referenced := m.ReferencedMessage
+	if referenced == nil {
+		retrieve, err := lib.GetReference(b.Discord.Logger(), s, &discordgo.MessageCreate{Message: m})
+		if err != nil {
+			return parserv5.Text{}
+		}
+		referenced = retrieve
+	}
+	return parserv5.Quote(s, referenced, b.Parser)
+}
+
 func (b *Bot) deleteMessageHandler(s *discordgo.Session, m *discordgo.MessageDelete) error {
 	reference, ok := b.Discord.Get(m.Message.ID)
 	if !ok {
@@ -182,8 +269,14 @@ func (b *Bot) deleteMessageHandler(s *discordgo.Session, m *discordgo.MessageDel
 		"channel", lib.ChannelNameID(s, m.Message.ChannelID),
 		"author", lib.GetUsername(m.Message),
 	)
-	err := b.Telegram.Delete(reference.Telegram)
+	if !telegram.Deletable(reference.Telegram) {
+		return b.redactMessage(s, reference, reference.Messages())
+	}
+	failed, err := b.deleteTelegram(reference)
 	if err != nil {
+		if errors.Is(err, telebot.ErrNoRightsToDelete) {
+			return b.redactMessage(s, reference, failed)
+		}
 		b.Discord.Logger().Error(
 			"Failed to delete message from Telegram",
 			"error", err,
@@ -203,8 +296,178 @@ func (b *Bot) deleteMessageHandler(s *discordgo.Session, m *discordgo.MessageDel
 	return nil
 }
 
+func (b *Bot) bulkDeleteHandler(s *discordgo.Session, m *discordgo.MessageDeleteBulk) error {
+	var (
+		references []*telebot.Message
+		expired    []discord.Tracked
+	)
+	tracked := make(map[int]discord.Tracked)
+	for _, id := range m.Messages {
+		reference, ok := b.Discord.Get(id)
+		if !ok {
+			continue
+		}
+		if !telegram.Deletable(reference.Telegram) {
+			expired = append(expired, reference)
+			continue
+		}
+		references = append(references, reference.Messages()...)
+		tracked[reference.Telegram.ID] = reference
+	}
+	if len(references) == 0 && len(expired) == 0 {
+		b.Discord.Logger().Debug(
+			"Messages were bulk deleted but none were tracked",
+			"count", len(m.Messages),
+			"channel", lib.ChannelNameID(s, m.ChannelID),
+		)
+		return nil
+	}
+	b.Discord.Logger().Debug(
+		"Messages were bulk deleted, deleting from Telegram",
+		"count", len(m.Messages),
+		"tracked", len(tracked)+len(expired),
+		"channel", lib.ChannelNameID(s, m.ChannelID),
+	)
+
+	var failed map[int]error
+	if len(references) > 0 {
+		failed = b.Telegram.DeleteMany(references)
+	}
+	var (
+		errs              []error
+		deleted, redacted int
+	)
+	// undeletable holds the messages of tracked messages that could only be partly deleted, by Discord ID.
+	undeletable := make(map[string][]*telebot.Message)
+	for telegramID, reference := range tracked {
+		var albumErrs []error
+		for _, message := range reference.Messages() {
+			if err, ok := failed[message.ID]; ok {
+				albumErrs = append(albumErrs, err)
+			}
+		}
+		err := errors.Join(albumErrs...)
+		if err == nil {
+			b.Discord.Unset(reference.Discord.ID)
+			deleted++
+			continue
+		}
+		if errors.Is(err, telebot.ErrNoRightsToDelete) {
+			expired = append(expired, reference)
+			undeletable[reference.Discord.ID] = undeleted(reference.Messages(), failed)
+			continue
+		}
+		b.Discord.Logger().Error(
+			"Failed to delete message from Telegram",
+			"error", err,
+			"message_id", reference.Discord.ID,
+			"telegram_id", telegramID,
+			"channel", lib.ChannelNameID(s, m.ChannelID),
+		)
+		errs = append(errs, fmt.Errorf("message %s: %w", reference.Discord.ID, err))
+	}
+	for _, reference := range expired {
+		messages, ok := undeletable[reference.Discord.ID]
+		if !ok {
+			messages = reference.Messages()
+		}
+		if err := b.redactMessage(s, reference, messages); err != nil {
+			errs = append(errs, fmt.Errorf("message %s: %w", reference.Discord.ID, err))
+			continue
+		}
+		redacted++
+	}
+
+	b.Discord.Logger().Info(
+		"Bulk delete mirrored to Telegram",
+		"count", len(m.Messages),
+		"deleted", deleted,
+		"redacted", redacted,
+		"failed", len(errs),
+		"channel", lib.ChannelNameID(s, m.ChannelID),
+	)
+	return errors.Join(errs...)
+}
+
+// deleteTelegram deletes every Telegram message reference was forwarded as.
+// It returns the messages that could not be deleted along with the error.
+func (b *Bot) deleteTelegram(reference discord.Tracked) ([]*telebot.Message, error) {
+	b.stopPollClose(reference.Discord.ID)
+	if len(reference.Album) == 0 && len(reference.Parts) == 0 {
+		if err := b.Telegram.Delete(reference.Telegram); err != nil {
+			return []*telebot.Message{reference.Telegram}, err
+		}
+		return nil, nil
+	}
+	failed := b.Telegram.DeleteMany(reference.Messages())
+	return undeleted(reference.Messages(), failed), errors.Join(slices.Collect(maps.Values(failed))...)
+}
+
+// undeleted returns the messages whose deletion failed, according to the errors of DeleteMany.
+func undeleted(messages []*telebot.Message, failed map[int]error) []*telebot.Message {
+	var remaining []*telebot.Message
+	for _, message := range messages {
+		if _, ok := failed[message.ID]; ok {
+			remaining = append(remaining, message)
+		}
+	}
+	return remaining
+}
+
+// redactTelegram replaces every one of messages with the redact placeholder.
+func (b *Bot) redactTelegram(messages []*telebot.Message) error {
+	var errs []error
+	for _, message := range messages {
+		_, err := b.Telegram.Redact(message, b.Options.RedactPlaceholder)
+		if err != nil && !errors.Is(err, telebot.ErrSameMessageContent) && !errors.Is(err, telebot.ErrMessageNotModified) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// redactMessage replaces the messages of reference that can no longer be deleted with the redact placeholder,
+// including its album items and text parts.
+func (b *Bot) redactMessage(s *discordgo.Session, reference discord.Tracked, messages []*telebot.Message) error {
+	b.Discord.Logger().Debug(
+		"Message is outside Telegram's deletion window, redacting instead",
+		"message_id", reference.Discord.ID,
+		"channel", lib.ChannelNameID(s, reference.Discord.ChannelID),
+		"author", lib.GetUsername(reference.Discord),
+		"count", len(messages),
+	)
+	err := b.redactTelegram(messages)
+	if err != nil {
+		b.Discord.Logger().Error(
+			"Failed to redact message in Telegram",
+			"error", err,
+			"message_id", reference.Discord.ID,
+			"channel", lib.ChannelNameID(s, reference.Discord.ChannelID),
+			"author", lib.GetUsername(reference.Discord),
+		)
+		return err
+	}
+	b.Discord.Unset(reference.Discord.ID)
+	b.Discord.Logger().Info(
+		"Successfully redacted message in Telegram",
+		"message_id", reference.Discord.ID,
+		"channel", lib.ChannelNameID(s, reference.Discord.ChannelID),
+		"author", lib.GetUsername(reference.Discord),
+	)
+	return nil
+}
+
 func (b *Bot) messageUpdateHandler(s *discordgo.Session, m *discordgo.MessageUpdate) error {
 	reference, ok := b.Discord.Get(m.Message.ID)
+	if !ok && m.BeforeUpdate != nil && parserv5.TranslateFlags(m.BeforeUpdate).Loading && !parserv5.TranslateFlags(m.Message).Skip() {
+		b.Discord.Logger().Debug(
+			"Loading message was completed, forwarding it",
+			"message_id", m.Message.ID,
+			"channel", lib.ChannelNameID(s, m.Message.ChannelID),
+			"author", lib.GetUsername(m.Message),
+		)
+		return b.mainHandler(s, &discordgo.MessageCreate{Message: m.Message})
+	}
 	if !ok {
 		b.Discord.Logger().Debug(
 			"Message was updated but not tracked",
@@ -214,6 +477,18 @@ func (b *Bot) messageUpdateHandler(s *discordgo.Session, m *discordgo.MessageUpd
 		)
 		return nil
 	}
+	if pollFinalized(m.Message) && !pollFinalized(reference.Discord) {
+		return b.finalizePoll(s, reference, m.Message)
+	}
+	if reference.Telegram.Poll != nil {
+		b.Discord.Logger().Debug(
+			"Message was updated but native polls cannot be edited",
+			"message_id", m.Message.ID,
+			"channel", lib.ChannelNameID(s, m.Message.ChannelID),
+			"author", lib.GetUsername(m.Message),
+		)
+		return nil
+	}
 	b.Discord.Logger().Debug(
 		"Message was updated, updating in Telegram",
 		"message_id", reference.Discord.ID,
@@ -226,46 +501,205 @@ func (b *Bot) messageUpdateHandler(s *discordgo.Session, m *discordgo.MessageUpd
 		"channel", lib.ChannelNameID(s, m.ChannelID),
 		"author", lib.GetUsername(m),
 	)
-	toSend, err := parserv5.Sendable(s, m.Message, parserv5.Parser(s, m.Message))
+	message := m.Message
+	// prefix is the quote of an untracked reply target or the attribution of a forward, as when it was sent.
+	var prefix parserv5.Text
+	if m.MessageReference != nil {
+		switch m.MessageReference.Type {
+		case discordgo.MessageReferenceTypeDefault:
+			if _, ok := b.Discord.Get(m.MessageReference.MessageID); !ok {
+				prefix = b.replyQuote(s, m.Message)
+			}
+		case discordgo.MessageReferenceTypeForward:
+			forward, err := lib.GetForward(b.Discord.Logger(), s, m.Message)
+			if err != nil {
+				return err
+			}
+			message = forward
+			prefix = parserv5.Parser(s, message, b.Parser)(lib.ForwardAttribution(s, m.Message))
+		}
+	}
+	descriptions := lib.AttachmentDescriptions(b.Discord.Logger(), s, m.Message, false)
+	toSend, err := parserv5.Sendable(s, message, descriptions, b.Parser)
 	if err != nil {
 		b.Discord.Logger().Error(
 			"Failed to process message",
 			"error", err,
-			"message_id", m.Message.ID,
-			"channel", lib.ChannelNameID(s, m.Message.ChannelID),
-			"author", lib.GetUsername(m),
+			"message_id", message.ID,
+			"channel", lib.ChannelNameID(s, message.ChannelID),
+			"author", lib.GetUsername(message),
 		)
 		return err
 	}
 	if toSend == nil {
 		b.Discord.Logger().Warn(
 			"Skipping message - no content to edit",
-			"message_id", m.Message.ID,
-			"channel", lib.ChannelNameID(s, m.Message.ChannelID),
-			"author", lib.GetUsername(m),
+			"message_id", message.ID,
+			"channel", lib.ChannelNameID(s, message.ChannelID),
+			"author", lib.GetUsername(message),
 		)
 		return nil
 	}
-	edited, err := b.Telegram.Edit(reference.Telegram, toSend)
+	toSend = parserv5.WithPrefix(toSend, prefix)
+	options := &telebot.SendOptions{
+		ThreadID:    reference.Telegram.ThreadID,
+		ReplyMarkup: b.replyMarkup(message, lib.MessageURL(m.Message)),
+	}
+	parserv5.TranslateFlags(message).Apply(options)
+	toSend, parts := splitOverflow(toSend)
+	edited, err := b.editMessage(s, reference, message, toSend, options)
+	if errors.Is(err, telebot.ErrSameMessageContent) || errors.Is(err, telebot.ErrMessageNotModified) {
+		edited, err = append([]*telebot.Message{reference.Telegram}, reference.Album...), nil
+	}
 	if err != nil {
-		if errors.Is(err, telebot.ErrSameMessageContent) || errors.Is(err, telebot.ErrMessageNotModified) {
-			return nil
-		}
 		b.Discord.Logger().Error(
 			"Failed to edit message in Telegram",
 			"error", err,
-			"message_id", m.Message.ID,
-			"channel", lib.ChannelNameID(s, m.Message.ChannelID),
-			"author", lib.GetUsername(m),
+			"message_id", message.ID,
+			"channel", lib.ChannelNameID(s, message.ChannelID),
+			"author", lib.GetUsername(message),
 		)
 		return err
 	}
-	b.Discord.Set(m.Message, edited)
+	b.Discord.Set(message, edited...)
+	previous := reference.Parts
+	if edited[0].ID != reference.Telegram.ID {
+		// The message was resent, its previous parts were deleted along with it.
+		previous = nil
+	}
+	forwarded, err := b.reconcileParts(edited[0], previous, parts, options)
+	b.Discord.SetParts(message.ID, forwarded)
+	if err != nil {
+		return err
+	}
 	b.Discord.Logger().Info(
 		"Successfully edited message in Telegram",
-		"message_id", m.Message.ID,
-		"channel", lib.ChannelNameID(s, m.Message.ChannelID),
-		"author", lib.GetUsername(m),
+		"message_id", message.ID,
+		"channel", lib.ChannelNameID(s, message.ChannelID),
+		"author", lib.GetUsername(message),
 	)
 	return nil
 }
+
+// editMessage mirrors a Discord edit onto the tracked Telegram message using the method that matches
+// the change: text edits, caption-only edits, media swaps, or a delete-and-resend when Telegram cannot
+// convert the message between text and media.
+func (b *Bot) editMessage(s *discordgo.Session, reference discord.Tracked, m *discordgo.Message, toSend any, options *telebot.SendOptions) ([]*telebot.Message, error) {
+	_, album := toSend.(telebot.Album)
+	if album || len(reference.Album) > 0 {
+		if !album || !parserv5.SameMedia(reference.Discord, m, b.Parser) {
+			return b.resendMessage(s, reference, m, toSend, options)
+		}
+		// The reply markup of an album is carried by a separate message and is left as is.
+		caption := *options
+		caption.ReplyMarkup = nil
+		edited, err := b.Telegram.EditCaption(reference.Telegram, parserv5.Caption(toSend), &caption)
+		if err != nil {
+			return nil, err
+		}
+		return append([]*telebot.Message{edited}, reference.Album...), nil
+	}
+
+	var (
+		edited *telebot.Message
+		err    error
+	)
+	captioned, media := toSend.(parserv5.Captioned)
+	_, inputtable := captioned.Media.(telebot.Inputtable)
+	switch {
+	case media != (reference.Telegram.Media() != nil):
+		return b.resendMessage(s, reference, m, toSend, options)
+	case !media:
+		edited, err = b.Telegram.Edit(reference.Telegram, toSend, options)
+	case parserv5.SameMedia(reference.Discord, m, b.Parser):
+		edited, err = b.Telegram.EditCaption(reference.Telegram, parserv5.Caption(toSend), options)
+	case !inputtable:
+		// Voice messages cannot be replaced through editMessageMedia.
+		return b.resendMessage(s, reference, m, toSend, options)
+	default:
+		edited, err = b.Telegram.Edit(reference.Telegram, toSend, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*telebot.Message{edited}, nil
+}
+
+// send sends toSend to Telegram, as media groups if it is an album.
+// If an album fails part way, the media groups already sent are deleted, as the whole album is sent again
+// when the message is retried.
+func (b *Bot) send(toSend any, options *telebot.SendOptions) ([]*telebot.Message, error) {
+	if album, ok := toSend.(telebot.Album); ok {
+		sent, err := b.Telegram.SendAlbum(album, options)
+		if err != nil && len(sent) > 0 {
+			for id, err := range b.Telegram.DeleteMany(sent) {
+				b.Telegram.Logger().Warn(
+					"Failed to delete partially sent album",
+					"error", err,
+					"telegram_id", id,
+				)
+			}
+			return nil, err
+		}
+		return sent, err
+	}
+	sent, err := b.Telegram.Send(toSend, options)
+	if err != nil {
+		return nil, err
+	}
+	return []*telebot.Message{sent}, nil
+}
+
+// resendMessage sends toSend as a new Telegram message in place of the tracked one,
+// replying to the same message, then deletes the old copy or redacts it if it can no longer be deleted.
+func (b *Bot) resendMessage(s *discordgo.Session, reference discord.Tracked, m *discordgo.Message, toSend any, options *telebot.SendOptions) ([]*telebot.Message, error) {
+	b.Discord.Logger().Debug(
+		"Message changed shape, resending",
+		"message_id", m.ID,
+		"channel", lib.ChannelNameID(s, m.ChannelID),
+		"author", lib.GetUsername(m),
+	)
+	resend := *options
+	resend.ReplyTo = reference.Telegram.ReplyTo
+	resent, err := b.send(toSend, &resend)
+	if err != nil {
+		return nil, err
+	}
+
+	failed, err := reference.Messages(), error(telebot.ErrNoRightsToDelete)
+	if telegram.Deletable(reference.Telegram) {
+		failed, err = b.deleteTelegram(reference)
+	}
+	if err != nil {
+		b.Discord.Logger().Warn(
+			"Failed to delete replaced message, redacting instead",
+			"error", err,
+			"message_id", m.ID,
+			"channel", lib.ChannelNameID(s, m.ChannelID),
+			"author", lib.GetUsername(m),
+		)
+		if err := b.redactTelegram(failed); err != nil {
+			b.Discord.Logger().Error(
+				"Failed to redact replaced message",
+				"error", err,
+				"message_id", m.ID,
+				"channel", lib.ChannelNameID(s, m.ChannelID),
+				"author", lib.GetUsername(m),
+			)
+		}
+	}
+	return resent, nil
+}
+
+// replyMarkup builds the inline keyboard of m, linking to url when a message or its
+// components can only be interacted with on Discord.
+func (b *Bot) replyMarkup(m *discordgo.Message, url string) *telebot.ReplyMarkup {
+	var jump string
+	switch {
+	case m.Poll != nil:
+		jump = "VOTE HERE (Discord)"
+	case b.Options.JumpButton:
+		jump = parserv5.JumpText
+	}
+	return parserv5.ReplyMarkup(m.Components, url, jump)
+}
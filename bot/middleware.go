@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"telegram-discord/lib"
+	"telegram-discord/lib/parser/parserv5"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/charmbracelet/log"
@@ -105,7 +106,11 @@ var (
 	ErrUserNotBot = errors.New("user is not a bot")
 )
 
+// OnlyBots skips messages sent by users, system messages such as pins or member joins are always let through.
 func OnlyBots(_ *discordgo.Session, m *discordgo.MessageCreate) error {
+	if parserv5.IsSystem(m.Message) {
+		return nil
+	}
 	user := lib.GetUser(m)
 	if user == nil {
 		return nil
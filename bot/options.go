@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"os"
+
+	"telegram-discord/lib"
+	"telegram-discord/lib/parser/parserv5"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Options configures how messages are mirrored from the registered Discord channel to the Telegram chat.
+type Options struct {
+	// RedactPlaceholder replaces the content of Telegram messages that can no longer be deleted
+	// once their Discord message is deleted.
+	RedactPlaceholder string
+
+	// NativePolls forwards Discord polls as native Telegram polls instead of a link to vote on Discord.
+	NativePolls bool
+
+	// JumpButton appends a button linking back to the Discord message to every forwarded message.
+	JumpButton bool
+
+	// SystemMessages enables rendering of each Discord system message type, such as pins or member joins.
+	// None are enabled by default.
+	SystemMessages map[discordgo.MessageType]bool
+
+	// EmojiMode selects how Discord custom emoji are rendered.
+	EmojiMode parserv5.EmojiMode
+
+	// EmojiMapping is the path to a JSON file mapping custom emoji names to Unicode emoji.
+	EmojiMapping string
+
+	// FileServer publishes attachments too large for Telegram. If it is not set,
+	// oversized attachments link to the Discord CDN.
+	FileServer parserv5.FileServer
+
+	// AltTextFormat is the Discord markdown line appended to captions for attachments with alt text,
+	// where %s is replaced by the description.
+	AltTextFormat string
+
+	// Renderer selects how formatted text is delivered to the Telegram chat: as MarkdownV2,
+	// as plain text with message entities, or as HTML.
+	Renderer parserv5.Renderer
+
+	// MarkupSamples is the directory where markup rejected by Telegram is saved as samples for parser
+	// regression tests. Rejected markup is only logged if it is empty.
+	MarkupSamples string
+}
+
+const defaultRedactPlaceholder = "🗑 This message was removed on Discord"
+
+// LoadOptions reads the mirroring options from the environment.
+func LoadOptions() Options {
+	return Options{
+		RedactPlaceholder: os.Getenv(lib.EnvRedactPlaceholder),
+		NativePolls:       os.Getenv(lib.EnvNativePolls) == "true",
+		JumpButton:        os.Getenv(lib.EnvJumpButton) == "true",
+		SystemMessages:    parserv5.ParseSystemTypes(os.Getenv(lib.EnvSystemMessages)),
+		EmojiMode:         parserv5.EmojiMode(os.Getenv(lib.EnvEmojiMode)),
+		EmojiMapping:      os.Getenv(lib.EnvEmojiMapping),
+		FileServer: parserv5.FileServer{
+			URL: os.Getenv(lib.EnvFileServerURL),
+			Dir: os.Getenv(lib.EnvFileServerDir),
+		},
+		AltTextFormat: os.Getenv(lib.EnvAltTextFormat),
+		Renderer:      parserv5.Renderer(os.Getenv(lib.EnvRenderer)),
+		MarkupSamples: os.Getenv(lib.EnvMarkupSamples),
+	}
+}
+
+func (o Options) withDefaults() Options {
+	if o.RedactPlaceholder == "" {
+		o.RedactPlaceholder = defaultRedactPlaceholder
+	}
+	if o.SystemMessages == nil {
+		o.SystemMessages = parserv5.ParseSystemTypes("")
+	}
+	if o.AltTextFormat == "" {
+		o.AltTextFormat = parserv5.DefaultAltTextFormat
+	}
+	if o.EmojiMode == "" {
+		o.EmojiMode = parserv5.EmojiModeName
+	}
+	if o.Renderer == "" {
+		o.Renderer = parserv5.RendererMarkdownV2
+	}
+	return o
+}
@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"errors"
+
+	"telegram-discord/lib/parser/parserv5"
+
+	"gopkg.in/telebot.v4"
+)
+
+// splitOverflow moves the text Telegram cannot send along with toSend out of it: the parts following
+// the first of a split text, or a caption too long for the media, split into parts of at most MaxText characters.
+// They are returned as text parts to be sent as replies to toSend.
+func splitOverflow(toSend any) (any, []parserv5.Text) {
+	if parts, ok := toSend.(parserv5.Parts); ok {
+		return parts[0], parts[1:]
+	}
+	caption := parserv5.Caption(toSend)
+	if caption.Len() <= parserv5.MaxCaption {
+		return toSend, nil
+	}
+	return parserv5.WithCaption(toSend, parserv5.Text{}), parserv5.SplitText(caption, parserv5.MaxText)
+}
+
+// partOptions returns the options of the text parts continuing first.
+func partOptions(first *telebot.Message, options *telebot.SendOptions) *telebot.SendOptions {
+	part := *options
+	part.ReplyTo = first
+	part.ReplyMarkup = nil
+	return &part
+}
+
+// reconcileParts makes the text parts following first match parts with parserv5.ReconcileParts, editing the
+// previous parts in place, sending the missing ones as replies to first and deleting those no longer needed.
+// It returns the parts now following first, even if some of them failed.
+func (b *Bot) reconcileParts(first *telebot.Message, previous []*telebot.Message, parts []parserv5.Text, options *telebot.SendOptions) ([]*telebot.Message, error) {
+	edit := func(part *telebot.Message, text parserv5.Text) (*telebot.Message, error) {
+		edited, err := b.Telegram.Edit(part, text, partOptions(first, options))
+		if errors.Is(err, telebot.ErrSameMessageContent) || errors.Is(err, telebot.ErrMessageNotModified) {
+			return part, nil
+		}
+		return edited, err
+	}
+	send := func(text parserv5.Text) (*telebot.Message, error) {
+		return b.Telegram.Send(text, partOptions(first, options))
+	}
+	remove := func(surplus []*telebot.Message) {
+		for id, err := range b.Telegram.DeleteMany(surplus) {
+			b.Telegram.Logger().Warn(
+				"Failed to delete surplus message part, redacting instead",
+				"error", err,
+				"telegram_id", id,
+			)
+			for _, part := range surplus {
+				if part.ID == id {
+					_, _ = b.Telegram.Redact(part, b.Options.RedactPlaceholder)
+				}
+			}
+		}
+	}
+
+	current, err := parserv5.ReconcileParts(previous, parts, edit, send, remove)
+	if err != nil {
+		b.Telegram.Logger().Error(
+			"Failed to send message parts",
+			"error", err,
+			"parts", len(parts),
+			"sent", len(current),
+		)
+	}
+	return current, err
+}
@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"time"
+
+	"telegram-discord/bot/discord"
+	"telegram-discord/lib"
+	"telegram-discord/lib/parser/parserv5"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// schedulePolls schedules the closing of every tracked native poll that is still open,
+// so that polls forwarded before a restart still close at their Discord expiry.
+func (b *Bot) schedulePolls() {
+	for _, tracked := range b.Discord.Polls() {
+		if tracked.Telegram.Poll.Closed || tracked.Discord.Poll == nil {
+			continue
+		}
+		b.schedulePollClose(tracked.Discord.ID, tracked.Discord.Poll.Expiry)
+	}
+}
+
+// schedulePollClose closes the native Telegram poll of a Discord message once the Discord poll expires,
+// replacing the timer previously scheduled for it.
+func (b *Bot) schedulePollClose(id string, expiry *time.Time) {
+	if expiry == nil {
+		return
+	}
+	b.Discord.Logger().Debug(
+		"Scheduling poll close",
+		"message_id", id,
+		"expiry", expiry,
+	)
+	b.pollsMu.Lock()
+	defer b.pollsMu.Unlock()
+	if b.pollTimers == nil {
+		b.pollTimers = make(map[string]*time.Timer)
+	}
+	if timer, ok := b.pollTimers[id]; ok {
+		timer.Stop()
+	}
+	b.pollTimers[id] = time.AfterFunc(time.Until(*expiry), func() {
+		b.stopPollClose(id)
+		_ = b.closePoll(id)
+	})
+}
+
+// stopPollClose cancels the scheduled closing of the native Telegram poll of a Discord message.
+func (b *Bot) stopPollClose(id string) {
+	b.pollsMu.Lock()
+	defer b.pollsMu.Unlock()
+	if timer, ok := b.pollTimers[id]; ok {
+		timer.Stop()
+		delete(b.pollTimers, id)
+	}
+}
+
+// closePoll stops the native Telegram poll of the Discord message id, if it is still open.
+// Polls are closed one at a time, so that a poll expiring as Discord finalizes it is only stopped once.
+func (b *Bot) closePoll(id string) error {
+	b.pollsMu.Lock()
+	defer b.pollsMu.Unlock()
+	reference, ok := b.Discord.Get(id)
+	if !ok || reference.Telegram.Poll == nil || reference.Telegram.Poll.Closed {
+		return nil
+	}
+	stopped, err := b.Telegram.StopPoll(reference.Telegram)
+	if err != nil {
+		b.Discord.Logger().Warn(
+			"Failed to close Telegram poll",
+			"error", err,
+			"message_id", reference.Discord.ID,
+		)
+		return err
+	}
+	b.Discord.Set(reference.Discord, stopped)
+	b.Discord.Logger().Info(
+		"Closed Telegram poll",
+		"message_id", reference.Discord.ID,
+	)
+	return nil
+}
+
+// finalizePoll posts the final results of a Discord poll as a reply to its Telegram copy
+// and closes the native Telegram poll if there is one.
+func (b *Bot) finalizePoll(s *discordgo.Session, reference discord.Tracked, m *discordgo.Message) error {
+	b.Discord.Logger().Debug(
+		"Poll was finalized, posting results to Telegram",
+		"message_id", m.ID,
+		"channel", lib.ChannelNameID(s, m.ChannelID),
+		"author", lib.GetUsername(m),
+	)
+	results := parserv5.Parser(s, m, b.Parser)(parserv5.PollResults(m.Poll))
+	_, err := b.Telegram.Send(results, &telebot.SendOptions{
+		ReplyTo:  reference.Telegram,
+		ThreadID: reference.Telegram.ThreadID,
+	})
+	if err != nil {
+		b.Discord.Logger().Error(
+			"Failed to post poll results to Telegram",
+			"error", err,
+			"message_id", m.ID,
+			"channel", lib.ChannelNameID(s, m.ChannelID),
+			"author", lib.GetUsername(m),
+		)
+		return err
+	}
+
+	b.stopPollClose(m.ID)
+	telegram := reference.Telegram
+	if err := b.closePoll(m.ID); err == nil {
+		if closed, ok := b.Discord.Get(m.ID); ok {
+			telegram = closed.Telegram
+		}
+	}
+	b.Discord.Set(m, telegram)
+	b.Discord.Logger().Info(
+		"Successfully posted poll results to Telegram",
+		"message_id", m.ID,
+		"channel", lib.ChannelNameID(s, m.ChannelID),
+		"author", lib.GetUsername(m),
+	)
+	return nil
+}
+
+func pollFinalized(m *discordgo.Message) bool {
+	return m != nil && m.Poll != nil && m.Poll.Results != nil && m.Poll.Results.Finalized
+}
@@ -0,0 +1,165 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"telegram-discord/lib/wrapper"
+
+	"gopkg.in/telebot.v4"
+)
+
+// markupOffsetRe matches the byte offset Telegram reports when it rejects the markup of a message.
+var markupOffsetRe = regexp.MustCompile(`byte offset (\d+)`)
+
+// markupExcerpt is the number of bytes of markup logged on each side of a rejected offset.
+const markupExcerpt = 40
+
+// MarkupSample is markup Telegram rejected, saved for parser regression tests along with the Discord
+// markdown it was rendered from.
+type MarkupSample struct {
+	Error     string `json:"error"`
+	ParseMode string `json:"parse_mode"`
+	Offset    int    `json:"offset"`
+	Markup    string `json:"markup"`
+	Source    string `json:"source"`
+}
+
+// sourced is content that keeps the Discord markdown it was parsed from.
+type sourced interface {
+	Source() string
+}
+
+// source returns the Discord markdown content was parsed from, or the captions of an album
+// separated by empty lines.
+func source(content any) string {
+	switch v := content.(type) {
+	case sourced:
+		return v.Source()
+	case telebot.Album:
+		var sources []string
+		for _, item := range v {
+			if s := source(item); s != "" {
+				sources = append(sources, s)
+			}
+		}
+		return strings.Join(sources, "\n\n")
+	default:
+		return ""
+	}
+}
+
+// isMarkupError reports whether err is Telegram rejecting the markup of a message.
+func isMarkupError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "can't parse entities")
+}
+
+// deliver renders content and passes it to send. If Telegram rejects its markup, the markup is reported
+// and content is passed to send again converted by Fallback, instead of failing with the same payload on every retry.
+// The files of content were read by the first attempt, so it is only sent again if they can be read again.
+func (b *Bot) deliver(content any, options *telebot.SendOptions, send func(any, *telebot.SendOptions) error) error {
+	rendered, renderedOptions := b.render(content, options)
+	err := send(rendered, renderedOptions)
+	if !isMarkupError(err) || b.Fallback == nil {
+		return err
+	}
+	b.reportMarkup(content, rendered, renderedOptions, err)
+	if !rewind(content) {
+		b.logger.Error(
+			"Cannot send the message again, its files cannot be read again",
+			"error", err,
+			"content_type", fmt.Sprintf("%T", content),
+		)
+		return err
+	}
+	return send(b.Fallback(content, options))
+}
+
+// rewind seeks the readers of the files of content back to their start, reporting whether they all can be
+// read again. Files without a reader are uploaded from disk or sent by reference, and are read again as is.
+func rewind(content any) bool {
+	switch v := content.(type) {
+	case telebot.Album:
+		for _, item := range v {
+			if !rewind(item) {
+				return false
+			}
+		}
+		return true
+	case telebot.Media:
+		file := v.MediaFile()
+		if file == nil || file.FileReader == nil {
+			return true
+		}
+		seeker, ok := file.FileReader.(io.Seeker)
+		if !ok {
+			return false
+		}
+		_, err := seeker.Seek(0, io.SeekStart)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// reportMarkup logs the markup of content rendered as rendered and rejected with err around the offending offset,
+// and saves it to Samples if it is set.
+func (b *Bot) reportMarkup(content any, rendered any, options *telebot.SendOptions, err error) {
+	sample := MarkupSample{
+		Error:  err.Error(),
+		Offset: -1,
+		Markup: wrapper.GetParsed(rendered),
+		Source: source(content),
+	}
+	if options != nil {
+		sample.ParseMode = options.ParseMode
+	}
+	if match := markupOffsetRe.FindStringSubmatch(err.Error()); match != nil {
+		sample.Offset, _ = strconv.Atoi(match[1])
+	}
+
+	excerpt := sample.Markup
+	if sample.Offset >= 0 && sample.Offset <= len(sample.Markup) {
+		excerpt = sample.Markup[max(0, sample.Offset-markupExcerpt):min(len(sample.Markup), sample.Offset+markupExcerpt)]
+	}
+	b.logger.Warn(
+		"Telegram rejected the message markup, sending it again without it",
+		"error", err,
+		"parse_mode", sample.ParseMode,
+		"offset", sample.Offset,
+		"excerpt", strings.ToValidUTF8(excerpt, "�"),
+	)
+
+	if b.Samples == "" {
+		return
+	}
+	if err := saveSample(b.Samples, sample); err != nil {
+		b.logger.Error(
+			"Failed to save rejected markup sample",
+			"error", err,
+			"dir", b.Samples,
+		)
+	}
+}
+
+func saveSample(dir string, sample MarkupSample) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating samples directory: %w", err)
+	}
+	data, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding sample: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d.json", time.Now().UnixNano()))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing sample: %w", err)
+	}
+	return nil
+}
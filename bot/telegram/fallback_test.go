@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"telegram-discord/lib/parser/parserv5"
+
+	"github.com/charmbracelet/log"
+	"gopkg.in/telebot.v4"
+)
+
+// TestDeliverFallback fakes Telegram rejecting the markup of media, and checks that the media is sent again
+// with its whole file and its caption formatted by entities.
+func TestDeliverFallback(t *testing.T) {
+	photo := []byte("photo data")
+	b := &Bot{
+		Render:   parserv5.RendererMarkdownV2.Render,
+		Fallback: parserv5.RendererEntities.Render,
+		logger:   log.New(io.Discard),
+	}
+	content := parserv5.Captioned{
+		Media:   &telebot.Photo{File: telebot.FromReader(bytes.NewReader(photo))},
+		Caption: parserv5.Plain("caption"),
+	}
+
+	var (
+		uploads [][]byte
+		sent    []*telebot.SendOptions
+	)
+	err := b.deliver(content, &telebot.SendOptions{}, func(content any, options *telebot.SendOptions) error {
+		data, err := io.ReadAll(content.(telebot.Media).MediaFile().FileReader)
+		if err != nil {
+			return err
+		}
+		uploads = append(uploads, data)
+		sent = append(sent, options)
+		if len(sent) == 1 {
+			return errors.New("telegram: Bad Request: can't parse entities: Can't find end of the entity starting at byte offset 3 (400)")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("deliver() = %v; want the fallback to succeed", err)
+	}
+	if len(uploads) != 2 {
+		t.Fatalf("deliver() sent %d times; want 2", len(uploads))
+	}
+	for i, upload := range uploads {
+		if !bytes.Equal(upload, photo) {
+			t.Errorf("upload %d = %q; want %q", i, upload, photo)
+		}
+	}
+	if sent[0].ParseMode != telebot.ModeMarkdownV2 {
+		t.Errorf("first parse mode = %q; want %q", sent[0].ParseMode, telebot.ModeMarkdownV2)
+	}
+	if sent[1].ParseMode != telebot.ModeDefault {
+		t.Errorf("fallback parse mode = %q; want none", sent[1].ParseMode)
+	}
+}
+
+// TestDeliverFallbackUnreadable checks that media whose file cannot be read again is not sent again.
+func TestDeliverFallbackUnreadable(t *testing.T) {
+	b := &Bot{
+		Render:   parserv5.RendererMarkdownV2.Render,
+		Fallback: parserv5.RendererEntities.Render,
+		logger:   log.New(io.Discard),
+	}
+	content := parserv5.Captioned{
+		Media:   &telebot.Document{File: telebot.FromReader(io.LimitReader(bytes.NewReader([]byte("data")), 4))},
+		Caption: parserv5.Plain("caption"),
+	}
+
+	var attempts int
+	rejected := errors.New("telegram: Bad Request: can't parse entities: Unexpected end of name token at byte offset 7 (400)")
+	err := b.deliver(content, &telebot.SendOptions{}, func(any, *telebot.SendOptions) error {
+		attempts++
+		return rejected
+	})
+	if !errors.Is(err, rejected) || attempts != 1 {
+		t.Errorf("deliver() = %v after %d attempts; want %v after 1", err, attempts, rejected)
+	}
+}
@@ -3,6 +3,7 @@ package telegram
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"gopkg.in/telebot.v4"
@@ -38,7 +39,11 @@ func (b *Bot) Send(content any, options *telebot.SendOptions) (*telebot.Message,
 		"content_type", fmt.Sprintf("%T", content),
 	)
 	chat := &telebot.Chat{ID: b.Channel}
-	reference, err := b.Bot.Send(chat, content, options)
+	var reference *telebot.Message
+	err := b.deliver(content, options, func(content any, options *telebot.SendOptions) (err error) {
+		reference, err = b.Bot.Send(chat, content, options)
+		return err
+	})
 	if err != nil {
 		b.logger.Error(
 			"Failed to send message",
@@ -62,7 +67,113 @@ func (b *Bot) Send(content any, options *telebot.SendOptions) (*telebot.Message,
 	return reference, nil
 }
 
-func (b *Bot) Edit(reference *telebot.Message, content any) (*telebot.Message, error) {
+// maxAlbum is the maximum number of items in a single media group.
+const maxAlbum = 10
+
+// albumMarkupText is the text of the message carrying the reply markup of an album.
+const albumMarkupText = "⤴️"
+
+// SendAlbum sends album as media groups of up to maxAlbum items. Photos and videos are grouped together,
+// while documents and audio are grouped separately, as Telegram does not allow mixing them.
+// Groups are sent in the order their first item appears in album, and only the first group replies to options.ReplyTo.
+// Media groups cannot carry a reply markup, so options.ReplyMarkup is sent in a short message replying to the album,
+// returned after the album messages so that it is tracked with them. If a group fails, the messages of the groups
+// already sent are returned with the error.
+func (b *Bot) SendAlbum(album telebot.Album, options *telebot.SendOptions) ([]*telebot.Message, error) {
+	if b.Channel == 0 {
+		b.logger.Warn("Cannot send album - channel not set")
+		return nil, fmt.Errorf("channel not set")
+	}
+
+	groups := make(map[string]telebot.Album)
+	var order []string
+	for _, item := range album {
+		kind := item.MediaType()
+		if kind == "photo" || kind == "video" {
+			kind = "media"
+		}
+		if _, ok := groups[kind]; !ok {
+			order = append(order, kind)
+		}
+		groups[kind] = append(groups[kind], item)
+	}
+
+	send := *options
+	send.ReplyMarkup = nil
+	chat := &telebot.Chat{ID: b.Channel}
+	var sent []*telebot.Message
+	for _, kind := range order {
+		for chunk := range slices.Chunk(groups[kind], maxAlbum) {
+			b.logger.Debug(
+				"Sending album to Telegram",
+				"channel_id", b.Channel,
+				"thread_id", b.ThreadID,
+				"type", kind,
+				"count", len(chunk),
+			)
+			var messages []telebot.Message
+			err := b.deliver(chunk, &send, func(content any, options *telebot.SendOptions) (err error) {
+				chunk := content.(telebot.Album)
+				if len(chunk) > 1 {
+					messages, err = b.Bot.SendAlbum(chat, chunk, options)
+					return err
+				}
+				// sendMediaGroup requires at least two items.
+				message, err := b.Bot.Send(chat, chunk[0], options)
+				if message != nil {
+					messages = []telebot.Message{*message}
+				}
+				return err
+			})
+			if err != nil {
+				b.logger.Error(
+					"Failed to send album",
+					"error", err,
+					"channel_id", b.Channel,
+					"thread_id", b.ThreadID,
+					"type", kind,
+					"count", len(chunk),
+				)
+				return sent, lib.ParsedError{
+					Message: fmt.Errorf("error sending album to telegram: %w", err),
+					Parsed:  wrapper.GetParsed(album),
+				}
+			}
+			for i := range messages {
+				sent = append(sent, &messages[i])
+			}
+			send.ReplyTo = nil
+		}
+	}
+
+	if options.ReplyMarkup != nil && len(sent) > 0 {
+		markup, err := b.Bot.Send(chat, albumMarkupText, &telebot.SendOptions{
+			ReplyTo:     sent[0],
+			ThreadID:    options.ThreadID,
+			ReplyMarkup: options.ReplyMarkup,
+		})
+		if err != nil {
+			b.logger.Warn(
+				"Failed to send album reply markup",
+				"error", err,
+				"channel_id", b.Channel,
+				"thread_id", b.ThreadID,
+			)
+		} else {
+			sent = append(sent, markup)
+		}
+	}
+
+	b.logger.Info(
+		"Album sent successfully",
+		"channel_id", b.Channel,
+		"thread_id", b.ThreadID,
+		"count", len(sent),
+	)
+	return sent, nil
+}
+
+func (b *Bot) Edit(reference *telebot.Message, content any, options *telebot.SendOptions) (*telebot.Message, error) {
 	if id, chatID := reference.MessageSig(); id == "" || chatID == 0 {
 		b.logger.Warn("Cannot edit message - invalid reference")
 		return nil, fmt.Errorf("invalid reference")
@@ -75,9 +186,17 @@ func (b *Bot) Edit(reference *telebot.Message, content any) (*telebot.Message, e
 		"thread_id", reference.ThreadID,
 	)
 
-	edited, err := b.Bot.Edit(reference, content, &telebot.SendOptions{
-		ParseMode: telebot.ModeMarkdownV2,
-		ThreadID:  reference.ThreadID,
+	var (
+		edited *telebot.Message
+		err    error
+	)
+	err = b.deliver(content, options, func(content any, options *telebot.SendOptions) (err error) {
+		if preview, ok := content.(wrapper.Preview); ok {
+			edited, err = wrapper.EditPreview(b.Bot, reference, preview, options)
+		} else {
+			edited, err = b.Bot.Edit(reference, content, options)
+		}
+		return err
 	})
 	if err != nil {
 		if !errors.Is(err, telebot.ErrSameMessageContent) && !errors.Is(err, telebot.ErrMessageNotModified) {
@@ -105,6 +224,56 @@ func (b *Bot) Edit(reference *telebot.Message, content any) (*telebot.Message, e
 	return edited, nil
 }
 
+// EditCaption replaces only the caption of a media message, keeping its media untouched.
+// The caption is rendered like the text of a message.
+func (b *Bot) EditCaption(reference *telebot.Message, caption any, options *telebot.SendOptions) (*telebot.Message, error) {
+	if id, chatID := reference.MessageSig(); id == "" || chatID == 0 {
+		b.logger.Warn("Cannot edit caption - invalid reference")
+		return nil, fmt.Errorf("invalid reference")
+	}
+
+	b.logger.Debug(
+		"Editing caption in Telegram",
+		"message_id", reference.ID,
+		"channel_id", reference.Chat.ID,
+		"thread_id", reference.ThreadID,
+	)
+
+	var edited *telebot.Message
+	err := b.deliver(caption, options, func(caption any, options *telebot.SendOptions) (err error) {
+		text, ok := caption.(string)
+		if !ok {
+			return fmt.Errorf("caption of type %T is not rendered to text", caption)
+		}
+		edited, err = b.Bot.EditCaption(reference, text, options)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, telebot.ErrSameMessageContent) && !errors.Is(err, telebot.ErrMessageNotModified) {
+			b.logger.Error(
+				"Failed to edit caption in Telegram",
+				"error", err,
+				"message_id", reference.ID,
+				"chat_id", reference.Chat.ID,
+				"thread_id", reference.ThreadID,
+			)
+		}
+		return nil, lib.ParsedError{
+			Message: fmt.Errorf("error editing caption: %w", err),
+			Parsed:  wrapper.GetParsed(caption),
+		}
+	}
+
+	b.logger.Info(
+		"Successfully edited caption in Telegram",
+		"message_id", reference.ID,
+		"chat_id", reference.Chat.ID,
+		"thread_id", reference.ThreadID,
+	)
+
+	return edited, nil
+}
+
 func (b *Bot) Delete(reference *telebot.Message) error {
 	if id, chatID := reference.MessageSig(); id == "" || chatID == 0 {
 		b.logger.Warn("Cannot delete message - invalid reference")
@@ -140,6 +309,171 @@ func (b *Bot) Delete(reference *telebot.Message) error {
 	return nil
 }
 
+// StopPoll closes a native poll so that no more votes can be cast, returning the message with the final poll.
+func (b *Bot) StopPoll(reference *telebot.Message) (*telebot.Message, error) {
+	if id, chatID := reference.MessageSig(); id == "" || chatID == 0 || reference.Poll == nil {
+		b.logger.Warn("Cannot stop poll - invalid reference")
+		return nil, fmt.Errorf("invalid reference")
+	}
+
+	b.logger.Debug(
+		"Stopping poll in Telegram",
+		"message_id", reference.ID,
+		"chat_id", reference.Chat.ID,
+		"thread_id", reference.ThreadID,
+	)
+
+	poll, err := b.Bot.StopPoll(reference)
+	if err != nil {
+		b.logger.Error(
+			"Failed to stop poll in Telegram",
+			"error", err,
+			"message_id", reference.ID,
+			"chat_id", reference.Chat.ID,
+			"thread_id", reference.ThreadID,
+		)
+		return nil, fmt.Errorf("error stopping poll: %w", err)
+	}
+
+	b.logger.Info(
+		"Successfully stopped poll in Telegram",
+		"message_id", reference.ID,
+		"chat_id", reference.Chat.ID,
+		"thread_id", reference.ThreadID,
+	)
+
+	stopped := *reference
+	stopped.Poll = poll
+	return &stopped, nil
+}
+
+// DeleteWindow is how long after sending a message bots are allowed to delete it.
+const DeleteWindow = 48 * time.Hour
+
+// Deletable reports whether reference is still within Telegram's deletion window.
+func Deletable(reference *telebot.Message) bool {
+	return time.Since(reference.Time()) < DeleteWindow
+}
+
+// Redact replaces the content of a message that can no longer be deleted with placeholder.
+// Text messages are edited to the placeholder, and media messages have their media replaced with a placeholder
+// captioned with it. Audio and voice messages cannot be replaced, so only their caption is and they are only
+// partly redacted.
+func (b *Bot) Redact(reference *telebot.Message, placeholder string) (*telebot.Message, error) {
+	if id, chatID := reference.MessageSig(); id == "" || chatID == 0 {
+		b.logger.Warn("Cannot redact message - invalid reference")
+		return nil, fmt.Errorf("invalid reference")
+	}
+
+	b.logger.Debug(
+		"Redacting message in Telegram",
+		"message_id", reference.ID,
+		"chat_id", reference.Chat.ID,
+		"thread_id", reference.ThreadID,
+	)
+
+	var (
+		redacted *telebot.Message
+		err      error
+	)
+	switch {
+	case reference.Poll != nil, reference.Sticker != nil, reference.VideoNote != nil:
+		err = fmt.Errorf("message of type %T has no text to redact", reference.Media())
+	case reference.Media() != nil:
+		if media := placeholderMedia(reference, placeholder); media != nil {
+			redacted, err = b.Bot.EditMedia(reference, media)
+			break
+		}
+		b.logger.Warn(
+			"Media cannot be replaced, only redacting its caption",
+			"message_id", reference.ID,
+			"chat_id", reference.Chat.ID,
+			"type", fmt.Sprintf("%T", reference.Media()),
+		)
+		redacted, err = b.Bot.EditCaption(reference, placeholder)
+	default:
+		redacted, err = b.Bot.Edit(reference, placeholder)
+	}
+	if err != nil {
+		b.logger.Error(
+			"Failed to redact message in Telegram",
+			"error", err,
+			"message_id", reference.ID,
+			"chat_id", reference.Chat.ID,
+			"thread_id", reference.ThreadID,
+		)
+		return nil, fmt.Errorf("error redacting message: %w", err)
+	}
+
+	b.logger.Info(
+		"Successfully redacted message in Telegram",
+		"message_id", reference.ID,
+		"chat_id", reference.Chat.ID,
+		"thread_id", reference.ThreadID,
+	)
+
+	return redacted, nil
+}
+
+// maxDeleteMany is the maximum number of message IDs accepted by a single deleteMessages call.
+const maxDeleteMany = 100
+
+// DeleteMany deletes the given messages using deleteMessages, grouped by chat and chunked to the API limit.
+// If a chunk fails, its messages are deleted one by one so that failures can be reported per message.
+// The returned map is keyed by the Telegram message ID of every message that could not be deleted.
+func (b *Bot) DeleteMany(references []*telebot.Message) map[int]error {
+	failed := make(map[int]error)
+	chats := make(map[int64][]telebot.Editable)
+	var order []int64
+	for _, reference := range references {
+		if id, chatID := reference.MessageSig(); id == "" || chatID == 0 {
+			b.logger.Warn("Cannot delete message - invalid reference")
+			failed[reference.ID] = fmt.Errorf("invalid reference")
+			continue
+		}
+		if _, ok := chats[reference.Chat.ID]; !ok {
+			order = append(order, reference.Chat.ID)
+		}
+		chats[reference.Chat.ID] = append(chats[reference.Chat.ID], reference)
+	}
+
+	for _, chatID := range order {
+		messages := chats[chatID]
+		for chunk := range slices.Chunk(messages, maxDeleteMany) {
+			b.logger.Debug(
+				"Deleting messages from Telegram",
+				"chat_id", chatID,
+				"count", len(chunk),
+			)
+			err := b.Bot.DeleteMany(chunk)
+			if err == nil {
+				continue
+			}
+			b.logger.Warn(
+				"Failed to bulk delete messages, deleting individually",
+				"error", err,
+				"chat_id", chatID,
+				"count", len(chunk),
+			)
+			for _, message := range chunk {
+				reference := message.(*telebot.Message)
+				if err := b.Delete(reference); err != nil {
+					failed[reference.ID] = err
+				}
+			}
+		}
+	}
+
+	b.logger.Info(
+		"Bulk deleted messages from Telegram",
+		"requested", len(references),
+		"deleted", len(references)-len(failed),
+		"failed", len(failed),
+	)
+
+	return failed
+}
+
 func (b *Bot) handleSendToThisChannel(c telebot.Context) error {
 	if b.Channel == c.Chat().ID && b.ThreadID == c.Message().ThreadID {
 		b.logger.Warn(
@@ -0,0 +1,44 @@
+package telegram
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"sync"
+
+	"gopkg.in/telebot.v4"
+)
+
+// placeholderImage is the gray image that redacted photos, videos and animations are replaced with.
+var placeholderImage = sync.OnceValue(func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 320, 180))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+})
+
+// placeholderMedia returns the media that replaces the media of reference when it is redacted, with placeholder
+// as its caption. Telegram only allows replacing media in an album with media of a compatible type, so documents
+// are replaced with a text document and other visual media with a placeholder image. Audio and voice messages
+// cannot be replaced, and nil is returned for them.
+func placeholderMedia(reference *telebot.Message, placeholder string) telebot.Inputtable {
+	switch {
+	case reference.Document != nil:
+		return &telebot.Document{
+			File:     telebot.FromReader(strings.NewReader(placeholder)),
+			FileName: "redacted.txt",
+			Caption:  placeholder,
+		}
+	case reference.Photo != nil, reference.Video != nil, reference.Animation != nil:
+		return &telebot.Photo{
+			File:    telebot.FromReader(bytes.NewReader(placeholderImage())),
+			Caption: placeholder,
+		}
+	default:
+		return nil
+	}
+}
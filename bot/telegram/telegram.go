@@ -14,6 +14,15 @@ type Bot struct {
 	Bot      *telebot.Bot
 	Channel  int64
 	ThreadID int
+	// Render converts content right before it is sent or edited, such as to deliver MarkdownV2 in another form.
+	// Content is sent as is if it is nil.
+	Render func(content any, options *telebot.SendOptions) (any, *telebot.SendOptions)
+	// Fallback converts content whose markup Telegram rejected before it is sent again.
+	// Content is not sent again if it is nil.
+	Fallback func(content any, options *telebot.SendOptions) (any, *telebot.SendOptions)
+	// Samples is the directory markup rejected by Telegram is saved to, for parser regression tests.
+	// Rejected markup is only logged if it is empty.
+	Samples string
 
 	logger *log.Logger
 }
@@ -51,6 +60,14 @@ func (b *Bot) Logger() *log.Logger {
 	return b.logger
 }
 
+// render converts content with Render, if it is set.
+func (b *Bot) render(content any, options *telebot.SendOptions) (any, *telebot.SendOptions) {
+	if b.Render == nil {
+		return content, options
+	}
+	return b.Render(content, options)
+}
+
 func (b *Bot) Start() error {
 	go b.Bot.Start()
 	b.logger.Info(
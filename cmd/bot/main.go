@@ -25,6 +25,7 @@ func main() {
 		TelegramToken:     os.Getenv(lib.EnvTelegramToken),
 		TelegramChannelID: os.Getenv(lib.EnvTelegramChannel),
 		TelegramThreadID:  os.Getenv(lib.EnvTelegramThread),
+		Options:           bot.LoadOptions(),
 	})
 	if err != nil {
 		log.Fatalf("error creating bot: %v", err)
@@ -42,6 +42,8 @@ func main() {
 		TelegramChannelID: os.Getenv(lib.EnvTelegramChannel),
 		TelegramThreadID:  os.Getenv(lib.EnvTelegramThread),
 		TelegramLogger:    writers[1],
+
+		Options: bot.LoadOptions(),
 	})
 	if err != nil {
 		log.Fatalf("error creating bot: %v", err)
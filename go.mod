@@ -12,6 +12,7 @@ require (
 	github.com/gomarkdown/markdown v0.0.0-20250810172220-2e2c11897d1a
 	github.com/joho/godotenv v1.5.1
 	github.com/muesli/termenv v0.16.0
+	golang.org/x/image v0.25.0
 	gopkg.in/telebot.v4 v4.0.0-beta.5
 )
 
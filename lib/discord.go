@@ -1,10 +1,13 @@
 package lib
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/charmbracelet/log"
@@ -99,6 +102,275 @@ func ChannelNameID(s *discordgo.Session, id string) string {
 	return fmt.Sprintf("%s (%s)", channel.Name, channel.ID)
 }
 
+func GuildName(s *discordgo.Session, id string) string {
+	if s == nil {
+		return "unknown"
+	}
+
+	guild, err := s.State.Guild(id)
+	if err != nil {
+		if errors.Is(err, discordgo.ErrStateNotFound) {
+			guild, err = s.Guild(id)
+			if err != nil {
+				return "unknown"
+			}
+		} else {
+			return "unknown"
+		}
+	}
+
+	return guild.Name
+}
+
+// GetForward returns the message forwarded by m. It is built from the snapshot embedded in m so that
+// forwards from inaccessible channels still work, falling back to fetching the original message.
+// The returned message keeps the ID, channel and author of m so that it is tracked as the forward itself.
+// ForwardAttribution renders who forwarded it and where it was forwarded from.
+func GetForward(logger *log.Logger, s *discordgo.Session, m *discordgo.Message) (*discordgo.Message, error) {
+	var forwarded *discordgo.Message
+	if hasSnapshot(m) {
+		forwarded = m.MessageSnapshots[0].Message
+	} else {
+		logger.Warn(
+			"Forwarded message has no snapshot, retrieving referenced message",
+			"message_id", m.ID,
+			"reference_id", m.MessageReference.MessageID,
+			"author", GetUsername(m),
+		)
+		retrieve, err := GetReference(logger, s, &discordgo.MessageCreate{Message: m})
+		if err != nil {
+			return nil, err
+		}
+		forwarded = retrieve
+	}
+
+	message := *forwarded
+	message.ID = m.ID
+	message.ChannelID = m.ChannelID
+	message.GuildID = m.GuildID
+	message.Author = m.Author
+	message.Member = m.Member
+	message.MessageReference = m.MessageReference
+	return &message, nil
+}
+
+// ForwardAttribution renders the Discord markdown header naming who forwarded m and where from.
+func ForwardAttribution(s *discordgo.Session, m *discordgo.Message) string {
+	source := "another channel"
+	if channel := ChannelName(s, m.MessageReference.ChannelID); channel != "unknown" {
+		source = "#" + EscapeMarkdown(channel)
+	}
+	if m.MessageReference.GuildID != "" {
+		if guild := GuildName(s, m.MessageReference.GuildID); guild != "unknown" {
+			source += " in " + EscapeMarkdown(guild)
+		}
+	}
+
+	var forwarder string
+	if user := GetUser(m); user != nil && user.DisplayName() != "" {
+		forwarder = fmt.Sprintf("*%s*: ", EscapeMarkdown(user.DisplayName()))
+	}
+	return fmt.Sprintf("%s↪️ _Forwarded from %s_", forwarder, source)
+}
+
+// EscapeMarkdown escapes the Discord markdown tokens in text so that it is rendered literally.
+func EscapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	">", `\>`,
+	"#", `\#`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+// MessageURL returns the link that opens m in Discord.
+func MessageURL(m *discordgo.Message) string {
+	guildID := m.GuildID
+	if guildID == "" {
+		guildID = "@me"
+	}
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, m.ChannelID, m.ID)
+}
+
+// descriptionWait is how long AttachmentDescriptions waits for the gateway payload of a message to be read.
+const descriptionWait = 2 * time.Second
+
+// descriptionExpiry is how long the attachment descriptions of a message are kept, so that edits reuse them.
+const descriptionExpiry = 10 * time.Minute
+
+type descriptionEntry struct {
+	// ready is closed once descriptions have been read from the gateway payload.
+	ready        chan struct{}
+	descriptions map[string]string
+	expiry       time.Time
+}
+
+var (
+	descriptionsMu sync.Mutex
+	descriptions   = make(map[string]*descriptionEntry)
+)
+
+// descriptionEntryFor returns the cached entry of the message id, creating a pending one if there is none.
+// Expired entries are evicted first, including pending ones whose payload never arrived.
+// It must be called with descriptionsMu held.
+func descriptionEntryFor(id string) *descriptionEntry {
+	now := time.Now()
+	for id, entry := range descriptions {
+		if now.After(entry.expiry) {
+			delete(descriptions, id)
+		}
+	}
+	entry, ok := descriptions[id]
+	if !ok {
+		entry = &descriptionEntry{ready: make(chan struct{}), expiry: time.Now().Add(descriptionExpiry)}
+		descriptions[id] = entry
+	}
+	return entry
+}
+
+type rawAttachments []struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// rawMessage is the part of a gateway or REST message payload holding attachment descriptions,
+// which discordgo does not decode.
+type rawMessage struct {
+	ID          string          `json:"id"`
+	Attachments *rawAttachments `json:"attachments"`
+	Snapshots   []struct {
+		Message struct {
+			Attachments rawAttachments `json:"attachments"`
+		} `json:"message"`
+	} `json:"message_snapshots"`
+}
+
+func (raw rawMessage) descriptions() map[string]string {
+	descriptions := make(map[string]string)
+	var all rawAttachments
+	if raw.Attachments != nil {
+		all = *raw.Attachments
+	}
+	for _, snapshot := range raw.Snapshots {
+		all = append(all, snapshot.Message.Attachments...)
+	}
+	for _, attachment := range all {
+		if attachment.Description != "" {
+			descriptions[attachment.ID] = attachment.Description
+		}
+	}
+	return descriptions
+}
+
+// StoreAttachmentDescriptions reads the attachment descriptions of the messages created or updated by e
+// for AttachmentDescriptions. It is registered as a handler of raw gateway events.
+func StoreAttachmentDescriptions(_ *discordgo.Session, e *discordgo.Event) {
+	if e.Type != "MESSAGE_CREATE" && e.Type != "MESSAGE_UPDATE" {
+		return
+	}
+	var raw rawMessage
+	if err := json.Unmarshal(e.RawData, &raw); err != nil || raw.ID == "" {
+		return
+	}
+	// Partial updates, like embeds being unfurled, do not list attachments and keep the cached descriptions.
+	if raw.Attachments == nil && len(raw.Snapshots) == 0 && e.Type == "MESSAGE_UPDATE" {
+		return
+	}
+
+	descriptionsMu.Lock()
+	defer descriptionsMu.Unlock()
+	entry := descriptionEntryFor(raw.ID)
+	entry.descriptions = raw.descriptions()
+	entry.expiry = time.Now().Add(descriptionExpiry)
+	select {
+	case <-entry.ready:
+	default:
+		close(entry.ready)
+	}
+}
+
+// AttachmentDescriptions returns the alt text of the attachments of m keyed by attachment ID, including those of
+// forwarded snapshots. discordgo does not decode attachment descriptions, so they are read from the gateway payload
+// of m by StoreAttachmentDescriptions, and fetched as raw JSON if the payload was not seen.
+// If wait is set, m was just received and its payload is waited for a while; otherwise, such as for edits,
+// the descriptions are fetched right away if they are not cached. Forwards without a snapshot, whose payload
+// does not hold the forwarded attachments, are always fetched from the forwarded message.
+func AttachmentDescriptions(logger *log.Logger, s *discordgo.Session, m *discordgo.Message, wait bool) map[string]string {
+	if s == nil || m.ID == "" {
+		return make(map[string]string)
+	}
+	if ref := m.MessageReference; ref != nil && ref.Type == discordgo.MessageReferenceTypeForward && !hasSnapshot(m) {
+		return fetchAttachmentDescriptions(logger, s, ref.ChannelID, ref.MessageID)
+	}
+	if !hasAttachments(m) {
+		return make(map[string]string)
+	}
+
+	descriptionsMu.Lock()
+	entry := descriptionEntryFor(m.ID)
+	descriptionsMu.Unlock()
+	if wait {
+		select {
+		case <-entry.ready:
+		case <-time.After(descriptionWait):
+			logger.Warn("Attachment descriptions not received from gateway, retrieving message", "message_id", m.ID)
+		}
+	}
+	select {
+	case <-entry.ready:
+		descriptionsMu.Lock()
+		defer descriptionsMu.Unlock()
+		return entry.descriptions
+	default:
+		return fetchAttachmentDescriptions(logger, s, m.ChannelID, m.ID)
+	}
+}
+
+// fetchAttachmentDescriptions retrieves the message id of the channel channelID as raw JSON
+// and returns the descriptions of its attachments.
+func fetchAttachmentDescriptions(logger *log.Logger, s *discordgo.Session, channelID, id string) map[string]string {
+	if channelID == "" || id == "" {
+		return make(map[string]string)
+	}
+	body, err := s.RequestWithBucketID("GET", discordgo.EndpointChannelMessage(channelID, id), nil, discordgo.EndpointChannelMessage(channelID, ""))
+	if err != nil {
+		logger.Error("Failed to retrieve attachment descriptions", "error", err, "message_id", id)
+		return make(map[string]string)
+	}
+	var raw rawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		logger.Error("Failed to decode attachment descriptions", "error", err, "message_id", id)
+		return make(map[string]string)
+	}
+	return raw.descriptions()
+}
+
+// hasSnapshot reports whether m holds a snapshot of the message it forwards.
+func hasSnapshot(m *discordgo.Message) bool {
+	return len(m.MessageSnapshots) > 0 && m.MessageSnapshots[0].Message != nil
+}
+
+// hasAttachments reports whether m or the messages it forwards have attachments.
+func hasAttachments(m *discordgo.Message) bool {
+	if len(m.Attachments) > 0 {
+		return true
+	}
+	for _, snapshot := range m.MessageSnapshots {
+		if snapshot.Message != nil && len(snapshot.Message.Attachments) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func GetReference(logger *log.Logger, s *discordgo.Session, m *discordgo.MessageCreate) (*discordgo.Message, error) {
 	logger.Debug(
 		"Processing message with reference",
@@ -11,6 +11,18 @@ const (
 	EnvTelegramChannel = "TELEGRAM_CHANNEL_ID"
 	EnvTelegramThread  = "TELEGRAM_THREAD_ID"
 	EnvTelegramToken   = "TELEGRAM_TOKEN"
+
+	EnvRedactPlaceholder = "REDACT_PLACEHOLDER"
+	EnvNativePolls       = "NATIVE_POLLS"
+	EnvJumpButton        = "JUMP_BUTTON"
+	EnvSystemMessages    = "SYSTEM_MESSAGES"
+	EnvEmojiMode         = "EMOJI_MODE"
+	EnvEmojiMapping      = "EMOJI_MAPPING"
+	EnvFileServerURL     = "FILE_SERVER_URL"
+	EnvFileServerDir     = "FILE_SERVER_DIR"
+	EnvAltTextFormat     = "ALT_TEXT_FORMAT"
+	EnvRenderer          = "RENDERER"
+	EnvMarkupSamples     = "MARKUP_SAMPLES_DIR"
 )
 
 func Set(key string, value string) error {
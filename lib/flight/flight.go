@@ -29,6 +29,12 @@ func NewCache[K comparable, V any](work func(K) (V, error)) Cache[K, V] {
 }
 
 func (p *Cache[K, V]) Get(k K) (V, error) {
+	return p.GetFunc(k, p.work)
+}
+
+// GetFunc is like Get, but computes a value that is neither cached nor pending with work
+// instead of the work of the cache.
+func (p *Cache[K, V]) GetFunc(k K, work func(K) (V, error)) (V, error) {
 	p.pmu.Lock()
 	p.fmu.RLock()
 	finished, ok := p.finished[k]
@@ -49,7 +55,7 @@ func (p *Cache[K, V]) Get(k K) (V, error) {
 	p.pending[k] = &j
 	p.pmu.Unlock()
 
-	j.val, j.err = p.work(k)
+	j.val, j.err = work(k)
 	if j.err == nil {
 		p.fmu.Lock()
 		p.finished[k] = j.val
@@ -0,0 +1,29 @@
+package parserv5
+
+import (
+	"fmt"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultAltTextFormat is the default Options.AltTextFormat.
+const DefaultAltTextFormat = "_Alt text: %s_"
+
+// altText renders the alt text line of attachment with the format of o, or an empty string if it has none.
+func altText(descriptions map[string]string, attachment *discordgo.MessageAttachment, o Options) string {
+	description, ok := descriptions[attachment.ID]
+	if !ok || o.AltTextFormat == "" {
+		return ""
+	}
+	return fmt.Sprintf(o.AltTextFormat, lib.EscapeMarkdown(description))
+}
+
+// withLine appends line to content on its own line, if it is not empty.
+func withLine(content string, line string) string {
+	if line == "" {
+		return content
+	}
+	return withLinks(content, []string{line})
+}
@@ -0,0 +1,85 @@
+package parserv5
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// embedFields renders fields, joining consecutive inline fields on a single line.
+func embedFields(fields []*discordgo.MessageEmbedField, p parser) Text {
+	var (
+		lines  []Text
+		inline []Text
+	)
+	flush := func() {
+		if len(inline) > 0 {
+			lines = append(lines, Join(" │ ", inline...))
+			inline = nil
+		}
+	}
+	for _, field := range fields {
+		name := Join("", p(field.Name), Plain(":")).bold()
+		if field.Inline {
+			inline = append(inline, Join(" ", name, p(field.Value)))
+			continue
+		}
+		flush()
+		lines = append(lines, Join("\n", name, p(field.Value)))
+	}
+	flush()
+	return Join("\n", lines...)
+}
+
+// paragraph prefixes t with a line break, leaving an empty line before it once it is joined on a new line.
+func paragraph(t Text) Text {
+	return Text{Nodes: append([]Node{&TextNode{Text: "\n"}}, t.Nodes...), source: "\n" + t.source}
+}
+
+// embedTimestamp renders the ISO 8601 timestamp of an embed the same way as a Discord timestamp in a message.
+func embedTimestamp(timestamp string) Text {
+	if timestamp == "" {
+		return Text{}
+	}
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return Text{}
+	}
+	return Text{
+		Nodes:  []Node{&TimestampNode{Timestamp: t.Unix(), Style: "f"}},
+		source: fmt.Sprintf("<t:%d:f>", t.Unix()),
+	}
+}
+
+// embedColors are the colored squares an embed color is approximated with.
+var embedColors = []struct {
+	square  string
+	r, g, b int
+}{
+	{"🟥", 221, 46, 68},
+	{"🟧", 244, 144, 12},
+	{"🟨", 253, 203, 88},
+	{"🟩", 120, 177, 89},
+	{"🟦", 85, 172, 238},
+	{"🟪", 170, 142, 214},
+	{"🟫", 193, 105, 79},
+	{"⬛", 49, 55, 61},
+	{"⬜", 230, 231, 232},
+}
+
+// embedColor returns the colored square closest to color, or an empty string if the embed has no color.
+func embedColor(color int) string {
+	if color == 0 {
+		return ""
+	}
+	r, g, b := color>>16&0xff, color>>8&0xff, color&0xff
+	closest, distance := "", -1
+	for _, c := range embedColors {
+		d := (r-c.r)*(r-c.r) + (g-c.g)*(g-c.g) + (b-c.b)*(b-c.b)
+		if distance == -1 || d < distance {
+			closest, distance = c.square, d
+		}
+	}
+	return closest
+}
@@ -0,0 +1,117 @@
+package parserv5
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// EmojiMode selects how Discord custom emoji are forwarded to Telegram.
+type EmojiMode string
+
+const (
+	// EmojiModeName renders custom emoji as their :name:.
+	EmojiModeName EmojiMode = "name"
+	// EmojiModeUnicode renders custom emoji as the Unicode emoji they are mapped to,
+	// falling back to their :name: when they are not mapped.
+	EmojiModeUnicode EmojiMode = "unicode"
+	// EmojiModeImage sends messages made of a single custom emoji as the emoji image,
+	// and renders other custom emoji like EmojiModeUnicode.
+	EmojiModeImage EmojiMode = "image"
+)
+
+// EmojiOptions configures the rendering of custom emoji.
+type EmojiOptions struct {
+	Mode EmojiMode
+	// Mapping maps custom emoji names to Unicode emoji.
+	Mapping map[string]string
+}
+
+// LoadEmojiMapping reads a JSON object mapping custom emoji names to Unicode emoji from path.
+// An empty path returns an empty mapping.
+func LoadEmojiMapping(path string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if path == "" {
+		return mapping, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading emoji mapping: %w", err)
+	}
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("error decoding emoji mapping: %w", err)
+	}
+	return mapping, nil
+}
+
+// EmojiNode represents a Discord custom emoji.
+type EmojiNode struct {
+	Name     string
+	ID       string
+	Animated bool
+}
+
+func (n *EmojiNode) String() string {
+	return escapeTelegram(n.text())
+}
+
+// text returns the :name: the emoji is rendered as. Mapped emoji are replaced by their Unicode emoji
+// before they are parsed.
+func (n *EmojiNode) text() string {
+	return ":" + n.Name + ":"
+}
+
+// URL returns the CDN link of the emoji image.
+func (n *EmojiNode) URL() string {
+	if n.Animated {
+		return fmt.Sprintf("https://cdn.discordapp.com/emojis/%s.gif", n.ID)
+	}
+	return fmt.Sprintf("https://cdn.discordapp.com/emojis/%s.png", n.ID)
+}
+
+var emojiOnlyRe = regexp.MustCompile(`^\s*<(a?):(\w+):(\d+)>\s*$`)
+
+// onlyEmoji returns the custom emoji m consists of, if its content is a single custom emoji
+// and it has nothing else to forward.
+func onlyEmoji(m *discordgo.Message) (*EmojiNode, bool) {
+	if len(m.Attachments) > 0 || len(m.Embeds) > 0 || len(m.StickerItems) > 0 {
+		return nil, false
+	}
+	parts := emojiOnlyRe.FindStringSubmatch(m.Content)
+	if parts == nil {
+		return nil, false
+	}
+	return &EmojiNode{Name: parts[2], ID: parts[3], Animated: parts[1] == "a"}, true
+}
+
+// emojiSendable sends emoji as its image, with caption rendered as the caption.
+func emojiSendable(emoji *EmojiNode, caption Text) (any, error) {
+	reader, err := lib.DefaultCache.Get(emoji.URL())
+	if err != nil {
+		return nil, err
+	}
+	if emoji.Animated {
+		return Captioned{Media: &telebot.Animation{
+			File:     telebot.FromReader(bytes.NewReader(reader)),
+			FileName: emoji.Name + ".gif",
+		}, Caption: caption}, nil
+	}
+	return Captioned{Media: &telebot.Photo{File: telebot.FromReader(bytes.NewReader(reader))}, Caption: caption}, nil
+}
+
+// emojiAuthor renders the author prefix Sendable adds to user messages, for use as an emoji caption.
+func emojiAuthor(m *discordgo.Message, p parser) Text {
+	user := lib.GetUser(m)
+	if user == nil || user.Bot || user.DisplayName() == "" || isForward(m) {
+		return Text{}
+	}
+	return p(fmt.Sprintf("*%s*:", strings.TrimSpace(user.DisplayName())))
+}
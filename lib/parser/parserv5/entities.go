@@ -0,0 +1,183 @@
+package parserv5
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Entities renders nodes as plain text and the Telegram entities formatting it, with offsets in UTF-16
+// code units. Text sent this way needs no parse mode and no escaping. Like Telegram, surrounding whitespace
+// is trimmed from the text.
+func Entities(nodes []Node) (string, telebot.Entities) {
+	var r entityRenderer
+	r.nodes(nodes)
+	text := r.text.String()
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	lead := utf16Length(text[:len(text)-len(trimmed)])
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	length := utf16Length(trimmed)
+
+	var entities telebot.Entities
+	for _, entity := range r.entities {
+		start := max(entity.Offset-lead, 0)
+		end := min(entity.Offset+entity.Length-lead, length)
+		if end <= start {
+			continue
+		}
+		entity.Offset, entity.Length = start, end-start
+		entities = append(entities, entity)
+	}
+	slices.SortStableFunc(entities, func(a, b telebot.MessageEntity) int {
+		if a.Offset != b.Offset {
+			return a.Offset - b.Offset
+		}
+		return b.Length - a.Length
+	})
+	return trimmed, entities
+}
+
+// nodesLength returns the length of the text of nodes in UTF-16 code units, without trimming it.
+func nodesLength(nodes []Node) int {
+	var r entityRenderer
+	r.nodes(nodes)
+	return r.length
+}
+
+func utf16Length(text string) int {
+	var length int
+	for _, c := range text {
+		length += utf16.RuneLen(c)
+	}
+	return length
+}
+
+type entityRenderer struct {
+	text strings.Builder
+	// length is the length of text in UTF-16 code units.
+	length   int
+	entities telebot.Entities
+}
+
+func (r *entityRenderer) write(text string) {
+	r.text.WriteString(text)
+	r.length += utf16Length(text)
+}
+
+// wrap renders children and formats them as an entity of kind, returning the entity if it is not empty.
+func (r *entityRenderer) wrap(kind telebot.EntityType, children func()) *telebot.MessageEntity {
+	start := r.length
+	children()
+	if r.length == start {
+		return nil
+	}
+	r.entities = append(r.entities, telebot.MessageEntity{Type: kind, Offset: start, Length: r.length - start})
+	return &r.entities[len(r.entities)-1]
+}
+
+func (r *entityRenderer) nodes(nodes []Node) {
+	for _, node := range nodes {
+		r.node(node)
+	}
+}
+
+func (r *entityRenderer) node(node Node) {
+	switch n := node.(type) {
+	case *TextNode:
+		if n.Raw {
+			r.write(strings.TrimPrefix(n.Text, "\\"))
+		} else {
+			r.write(n.Text)
+		}
+	case *FormattingNode:
+		r.wrap(formattingEntity(n.Format), func() { r.nodes(n.Children) })
+	case *CodeNode:
+		r.wrap(telebot.EntityCode, func() { r.write(n.Text) })
+	case *CodeBlockNode:
+		if strings.TrimSpace(n.Text) == "" {
+			return
+		}
+		language, code := codeLanguage(n.Text)
+		if entity := r.wrap(telebot.EntityCodeBlock, func() { r.write(code) }); entity != nil {
+			entity.Language = language
+		}
+	case *QuoteBlockNode:
+		r.quote(func() { r.nodes(n.Children) })
+		r.write("\n")
+	case *HeaderNode:
+		switch n.Level {
+		case 1:
+			r.quote(func() { r.wrap(telebot.EntityBold, func() { r.nodes(n.Children) }) })
+		case 2:
+			r.quote(func() { r.nodes(n.Children) })
+		case 3:
+			r.wrap(telebot.EntityBold, func() { r.nodes(n.Children) })
+		default:
+			r.nodes(n.Children)
+			return
+		}
+		r.write("\n")
+	case *LinkNode:
+		label := func() { r.write(n.Text) }
+		if n.Children != nil {
+			label = func() { r.nodes(n.Children) }
+		}
+		if entity := r.wrap(telebot.EntityTextLink, label); entity != nil {
+			entity.URL = n.URL
+		}
+	case *MentionNode:
+		r.write(n.Text)
+	case *EmojiNode:
+		r.write(n.text())
+	case *TimestampNode:
+		primary, secondary := n.parts()
+		r.wrap(telebot.EntityBold, func() { r.write(primary) })
+		if secondary != "" {
+			r.write(" (" + secondary + ")")
+		}
+	}
+}
+
+// quote renders children as a blockquote, extending the blockquote of the previous line if there is one.
+func (r *entityRenderer) quote(children func()) {
+	start := r.length
+	entity := r.wrap(telebot.EntityBlockquote, children)
+	if entity == nil {
+		return
+	}
+	for i := len(r.entities) - 2; i >= 0; i-- {
+		previous := &r.entities[i]
+		if previous.Type == telebot.EntityBlockquote && previous.Offset+previous.Length+1 == start {
+			previous.Length += 1 + entity.Length
+			r.entities = slices.Delete(r.entities, len(r.entities)-1, len(r.entities))
+			return
+		}
+	}
+}
+
+// formattingEntity returns the entity type of a FormattingNode format.
+func formattingEntity(format string) telebot.EntityType {
+	switch format {
+	case "**":
+		return telebot.EntityBold
+	case "__":
+		return telebot.EntityUnderline
+	case "~~":
+		return telebot.EntityStrikethrough
+	case "||":
+		return telebot.EntitySpoiler
+	default:
+		return telebot.EntityItalic
+	}
+}
+
+// codeLanguage separates the language on the first line of a code block from its code.
+func codeLanguage(text string) (string, string) {
+	if first, rest, ok := strings.Cut(text, "\n"); ok && first != "" && !strings.ContainsAny(first, " \t") {
+		return first, rest
+	}
+	return "", text
+}
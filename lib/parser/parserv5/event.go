@@ -0,0 +1,95 @@
+package parserv5
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// EventSendable renders a Discord scheduled event as a Telegram event card.
+// It returns a Captioned photo when the event has a cover image, or a Text otherwise.
+// Cards too long for a caption are sent as text, leaving the cover image out.
+func EventSendable(s *discordgo.Session, event *discordgo.GuildScheduledEvent, o Options) (any, error) {
+	card := EventCard(s, event, o)
+	if event.Image == "" || card.Len() > MaxCaption {
+		return card, nil
+	}
+	reader, err := lib.DefaultCache.Get(EventImageURL(event))
+	if err != nil {
+		return nil, err
+	}
+	return Captioned{Media: &telebot.Photo{File: telebot.FromReader(bytes.NewReader(reader))}, Caption: card}, nil
+}
+
+// EventCard renders the title, time, location, status and description of a Discord scheduled event
+// as a Text. Times are rendered the same way as Discord timestamps in messages.
+func EventCard(s *discordgo.Session, event *discordgo.GuildScheduledEvent, o Options) Text {
+	var sb strings.Builder
+	name := lib.EscapeMarkdown(event.Name)
+	if event.Status == discordgo.GuildScheduledEventStatusCanceled {
+		fmt.Fprintf(&sb, "📅 ~~**%s**~~\n", name)
+	} else {
+		fmt.Fprintf(&sb, "📅 **%s**\n", name)
+	}
+
+	fmt.Fprintf(&sb, "🕒 <t:%d:F>", event.ScheduledStartTime.Unix())
+	if event.ScheduledEndTime != nil {
+		fmt.Fprintf(&sb, " until <t:%d:F>", event.ScheduledEndTime.Unix())
+	}
+	sb.WriteString("\n")
+
+	if location := eventLocation(s, event); location != "" {
+		fmt.Fprintf(&sb, "📍 %s\n", location)
+	}
+
+	switch event.Status {
+	case discordgo.GuildScheduledEventStatusActive:
+		sb.WriteString("🔴 **Happening now**\n")
+	case discordgo.GuildScheduledEventStatusCompleted:
+		sb.WriteString("✅ _This event has ended_\n")
+	case discordgo.GuildScheduledEventStatusCanceled:
+		sb.WriteString("❌ **This event was cancelled**\n")
+	}
+
+	if event.Description != "" {
+		sb.WriteString("\n" + event.Description + "\n")
+	}
+	return Parser(s, nil, o)(sb.String())
+}
+
+// EventStarting renders the notice posted when a Discord scheduled event starts.
+func EventStarting(s *discordgo.Session, event *discordgo.GuildScheduledEvent, o Options) Text {
+	text := fmt.Sprintf("🔴 **%s** is starting now!", lib.EscapeMarkdown(event.Name))
+	if location := eventLocation(s, event); location != "" {
+		text += fmt.Sprintf("\n📍 %s", location)
+	}
+	return Parser(s, nil, o)(text)
+}
+
+// EventURL returns the link that opens event in Discord.
+func EventURL(event *discordgo.GuildScheduledEvent) string {
+	return fmt.Sprintf("https://discord.com/events/%s/%s", event.GuildID, event.ID)
+}
+
+// EventImageURL returns the CDN link of the cover image of event.
+func EventImageURL(event *discordgo.GuildScheduledEvent) string {
+	return fmt.Sprintf("https://cdn.discordapp.com/guild-events/%s/%s.png?size=1024", event.ID, event.Image)
+}
+
+func eventLocation(s *discordgo.Session, event *discordgo.GuildScheduledEvent) string {
+	if event.EntityType == discordgo.GuildScheduledEventEntityTypeExternal {
+		return lib.EscapeMarkdown(event.EntityMetadata.Location)
+	}
+	if event.ChannelID == "" {
+		return ""
+	}
+	if channel := lib.ChannelName(s, event.ChannelID); channel != "unknown" {
+		return "#" + lib.EscapeMarkdown(channel)
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package parserv5
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// Flags is the Telegram translation of a Discord message's flags.
+type Flags struct {
+	// Ephemeral messages are only visible to the user who invoked an interaction.
+	Ephemeral bool
+	// Loading messages are interaction responses where the bot is still "thinking".
+	Loading bool
+	// Silent messages were sent with @silent and should not notify.
+	Silent bool
+	// NoPreview messages had their embeds suppressed, so link previews are disabled.
+	NoPreview bool
+}
+
+// TranslateFlags reads the flags of m that affect how it is mirrored to Telegram.
+func TranslateFlags(m *discordgo.Message) Flags {
+	return Flags{
+		Ephemeral: m.Flags&discordgo.MessageFlagsEphemeral != 0,
+		Loading:   m.Flags&discordgo.MessageFlagsLoading != 0,
+		Silent:    m.Flags&discordgo.MessageFlagsSuppressNotifications != 0,
+		NoPreview: m.Flags&discordgo.MessageFlagsSuppressEmbeds != 0,
+	}
+}
+
+// Skip reports whether the message should not be forwarded at all.
+func (f Flags) Skip() bool {
+	return f.Ephemeral || f.Loading
+}
+
+// Apply sets the send options matching f.
+func (f Flags) Apply(options *telebot.SendOptions) {
+	options.DisableNotification = f.Silent
+	options.DisableWebPagePreview = f.NoPreview
+}
+
+// spoilerPrefix is prepended by Discord to the filename of attachments marked as spoilers.
+const spoilerPrefix = "SPOILER_"
+
+// isSpoiler reports whether attachment was marked as a spoiler on Discord.
+func isSpoiler(attachment *discordgo.MessageAttachment) bool {
+	return strings.HasPrefix(attachment.Filename, spoilerPrefix)
+}
@@ -0,0 +1,60 @@
+package parserv5
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// embedGalleries groups embeds sharing the same URL, which is how Discord represents a single
+// link preview with several images. Embeds without a URL form a gallery on their own.
+func embedGalleries(embeds []*discordgo.MessageEmbed) [][]*discordgo.MessageEmbed {
+	var galleries [][]*discordgo.MessageEmbed
+	index := make(map[string]int)
+	for _, embed := range embeds {
+		if embed.URL != "" {
+			if i, ok := index[embed.URL]; ok {
+				galleries[i] = append(galleries[i], embed)
+				continue
+			}
+			index[embed.URL] = len(galleries)
+		}
+		galleries = append(galleries, []*discordgo.MessageEmbed{embed})
+	}
+	return galleries
+}
+
+// galleryImages returns the full images of gallery, or the first thumbnail if none of its embeds has one.
+func galleryImages(gallery []*discordgo.MessageEmbed) []string {
+	var images []string
+	for _, embed := range gallery {
+		if embed.Image != nil && embed.Image.URL != "" {
+			images = append(images, embed.Image.URL)
+		}
+	}
+	if len(images) > 0 {
+		return images
+	}
+	for _, embed := range gallery {
+		if embed.Thumbnail != nil && embed.Thumbnail.URL != "" {
+			return []string{embed.Thumbnail.URL}
+		}
+	}
+	return nil
+}
+
+// galleryAlbum sends the images of a gallery as a single album, captioned with the shared embed text.
+func galleryAlbum(images []string, caption Text) (any, error) {
+	album := make(telebot.Album, 0, len(images))
+	for i, url := range images {
+		media, err := photoSendable(url, false)
+		if err != nil {
+			return nil, err
+		}
+		item := AlbumItem{Inputtable: media.(telebot.Inputtable)}
+		if i == 0 {
+			item.Caption = caption
+		}
+		album = append(album, item)
+	}
+	return album, nil
+}
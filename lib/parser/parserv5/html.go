@@ -0,0 +1,120 @@
+package parserv5
+
+import (
+	"html"
+	"strings"
+)
+
+// maxQuoteLines is the number of lines above which blockquotes are rendered collapsed.
+const maxQuoteLines = 3
+
+// HTML renders nodes as the subset of HTML supported by Telegram's HTML parse mode.
+func HTML(nodes []Node) string {
+	var sb strings.Builder
+	renderHTML(&sb, nodes)
+	return sb.String()
+}
+
+func renderHTML(sb *strings.Builder, nodes []Node) {
+	for i := 0; i < len(nodes); i++ {
+		switch n := nodes[i].(type) {
+		case *TextNode:
+			if n.Raw {
+				sb.WriteString(html.EscapeString(strings.TrimPrefix(n.Text, "\\")))
+			} else {
+				sb.WriteString(html.EscapeString(n.Text))
+			}
+		case *FormattingNode:
+			tag := formattingTag(n.Format)
+			sb.WriteString("<" + tag + ">")
+			renderHTML(sb, n.Children)
+			sb.WriteString("</" + tag + ">")
+		case *CodeNode:
+			sb.WriteString("<code>" + html.EscapeString(n.Text) + "</code>")
+		case *CodeBlockNode:
+			if strings.TrimSpace(n.Text) == "" {
+				continue
+			}
+			language, code := codeLanguage(n.Text)
+			if language == "" {
+				sb.WriteString("<pre>" + html.EscapeString(code) + "</pre>")
+				continue
+			}
+			sb.WriteString(`<pre><code class="language-` + html.EscapeString(language) + `">`)
+			sb.WriteString(html.EscapeString(code) + "</code></pre>")
+		case *QuoteBlockNode:
+			// Consecutive quote lines are rendered as a single blockquote.
+			lines := []*QuoteBlockNode{n}
+			for i+1 < len(nodes) {
+				next, ok := nodes[i+1].(*QuoteBlockNode)
+				if !ok {
+					break
+				}
+				lines = append(lines, next)
+				i++
+			}
+			if len(lines) > maxQuoteLines {
+				sb.WriteString("<blockquote expandable>")
+			} else {
+				sb.WriteString("<blockquote>")
+			}
+			for j, line := range lines {
+				if j > 0 {
+					sb.WriteString("\n")
+				}
+				renderHTML(sb, line.Children)
+			}
+			sb.WriteString("</blockquote>")
+			if i+1 < len(nodes) {
+				sb.WriteString("\n")
+			}
+		case *HeaderNode:
+			var inner strings.Builder
+			renderHTML(&inner, n.Children)
+			switch n.Level {
+			case 1:
+				sb.WriteString("<blockquote><b>" + inner.String() + "</b></blockquote>\n")
+			case 2:
+				sb.WriteString("<blockquote>" + inner.String() + "</blockquote>\n")
+			case 3:
+				sb.WriteString("<b>" + inner.String() + "</b>\n")
+			default:
+				sb.WriteString(inner.String())
+			}
+		case *LinkNode:
+			sb.WriteString(`<a href="` + html.EscapeString(n.URL) + `">`)
+			if n.Children != nil {
+				renderHTML(sb, n.Children)
+			} else {
+				sb.WriteString(html.EscapeString(n.Text))
+			}
+			sb.WriteString("</a>")
+		case *MentionNode:
+			sb.WriteString(html.EscapeString(n.Text))
+		case *EmojiNode:
+			sb.WriteString(html.EscapeString(n.text()))
+		case *TimestampNode:
+			primary, secondary := n.parts()
+			sb.WriteString("<b>" + html.EscapeString(primary) + "</b>")
+			if secondary != "" {
+				sb.WriteString(" (" + html.EscapeString(secondary) + ")")
+			}
+		}
+	}
+}
+
+// formattingTag returns the Telegram HTML tag of a FormattingNode format.
+func formattingTag(format string) string {
+	switch format {
+	case "**":
+		return "b"
+	case "__":
+		return "u"
+	case "~~":
+		return "s"
+	case "||":
+		return "tg-spoiler"
+	default:
+		return "i"
+	}
+}
@@ -0,0 +1,106 @@
+package parserv5
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif"
+
+	"telegram-discord/lib"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	// maxPhotoDimensions is the largest sum of width and height Telegram accepts for a photo.
+	maxPhotoDimensions = 10000
+	// maxPhotoRatio is the most extreme aspect ratio Telegram accepts for a photo.
+	maxPhotoRatio = 20
+	// maxPhotoSide is the longest side photos are downscaled to, Telegram does not display them any larger.
+	maxPhotoSide = 2560
+	// photoQuality is the JPEG quality of re-encoded photos.
+	photoQuality = 90
+)
+
+var errPhotoRatio = errors.New("aspect ratio too extreme for a Telegram photo")
+
+// processedPhoto returns the image at url processed by processPhoto. It is kept in lib.DefaultCache
+// next to the original bytes, under the URL prefixed with photo:.
+func processedPhoto(url string) ([]byte, error) {
+	return lib.DefaultCache.GetFunc("photo:"+url, func(string) ([]byte, error) {
+		data, err := lib.DefaultCache.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		return processPhoto(data)
+	})
+}
+
+// photoSendable returns the image at url as a *telebot.Photo processed to fit Telegram's photo constraints,
+// or as a *telebot.Document of the original bytes when it cannot be converted.
+func photoSendable(url string, spoiler bool) (telebot.Media, error) {
+	photo, err := processedPhoto(url)
+	if err == nil {
+		return &telebot.Photo{
+			File:       telebot.FromReader(bytes.NewReader(photo)),
+			HasSpoiler: spoiler,
+		}, nil
+	}
+	original, err := lib.DefaultCache.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	return &telebot.Document{
+		File: telebot.FromReader(bytes.NewReader(original)),
+	}, nil
+}
+
+// processPhoto decodes data and, unless it is already a JPEG or PNG Telegram accepts as a photo,
+// downscales it and re-encodes it as PNG for PNG sources or JPEG otherwise.
+func processPhoto(data []byte) ([]byte, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("error decoding image: empty image")
+	}
+	if max(width, height) > maxPhotoRatio*min(width, height) {
+		return nil, errPhotoRatio
+	}
+	if (format == "jpeg" || format == "png") && len(data) <= MaxPhotoSize && width+height <= maxPhotoDimensions {
+		return data, nil
+	}
+
+	if side := max(width, height); side > maxPhotoSide {
+		scaled := image.NewRGBA(image.Rect(0, 0, width*maxPhotoSide/side, height*maxPhotoSide/side))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Over, nil)
+		img = scaled
+	}
+
+	var buf bytes.Buffer
+	if format == "png" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("error encoding image: %w", err)
+		}
+		if buf.Len() <= MaxPhotoSize {
+			return buf.Bytes(), nil
+		}
+		buf.Reset()
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: photoQuality}); err != nil {
+		return nil, fmt.Errorf("error encoding image: %w", err)
+	}
+	if buf.Len() > MaxPhotoSize {
+		return nil, fmt.Errorf("image too large after re-encoding: %d bytes", buf.Len())
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package parserv5
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// MaxUploadSize is the largest file a bot can upload to Telegram.
+	MaxUploadSize = 50 << 20
+	// MaxPhotoSize is the largest photo a bot can upload to Telegram, larger images are sent as documents.
+	MaxPhotoSize = 10 << 20
+)
+
+// FileServer publishes attachments too large for Telegram from a local directory,
+// as Discord CDN links expire after a while.
+type FileServer struct {
+	// URL is the public address Dir is served at.
+	URL string
+	// Dir is the directory oversized attachments are downloaded to.
+	Dir string
+}
+
+// downloads holds the paths of the attachments being downloaded to a file server.
+var downloads sync.Map
+
+// Link returns the address oversized attachments can be downloaded from: the file server if it is configured,
+// or the Discord CDN otherwise. Attachments are downloaded to the file server in the background, so that
+// sending a message does not wait for them. Files are stored by attachment ID, so that edits and retries
+// of a message reuse the file downloaded or being downloaded instead of downloading it again.
+func (f FileServer) Link(logger *log.Logger, attachment *discordgo.MessageAttachment) string {
+	if f.URL == "" || f.Dir == "" {
+		return attachment.URL
+	}
+	name := filepath.Base(attachment.Filename)
+	path := filepath.Join(f.Dir, attachment.ID, name)
+	if _, err := os.Stat(path); err != nil {
+		if _, downloading := downloads.LoadOrStore(path, struct{}{}); !downloading {
+			go func() {
+				defer downloads.Delete(path)
+				if err := lib.DownloadFile(attachment.URL, path); err != nil {
+					logger.Error(
+						"Failed to download attachment to file server",
+						"error", err,
+						"attachment_id", attachment.ID,
+						"filename", attachment.Filename,
+					)
+				}
+			}()
+		}
+	}
+	return strings.TrimSuffix(f.URL, "/") + "/" + attachment.ID + "/" + url.PathEscape(name)
+}
+
+// tooLarge reports whether attachment exceeds the Telegram upload limit.
+func tooLarge(attachment *discordgo.MessageAttachment) bool {
+	return attachment.Size > MaxUploadSize
+}
+
+// downloadLink renders a Discord markdown link to download attachment from the file server of o, for attachments
+// that cannot be uploaded to Telegram.
+func downloadLink(attachment *discordgo.MessageAttachment, o Options) string {
+	return fmt.Sprintf("📎 [%s](%s) _(%s)_",
+		lib.EscapeMarkdown(attachment.Filename),
+		o.Files.Link(o.logger(), attachment),
+		formatSize(attachment.Size),
+	)
+}
+
+// withLinks appends links to content on their own lines.
+func withLinks(content string, links []string) string {
+	if len(links) == 0 {
+		return content
+	}
+	if content == "" {
+		return strings.Join(links, "\n")
+	}
+	return content + "\n" + strings.Join(links, "\n")
+}
+
+func formatSize(size int) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	default:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+}
@@ -0,0 +1,56 @@
+package parserv5
+
+import (
+	"slices"
+
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	// MaxCaption is the longest caption Telegram accepts on media, in UTF-16 code units after parsing.
+	MaxCaption = 1024
+	// MaxText is the longest text Telegram accepts in a message, in UTF-16 code units after parsing.
+	MaxText = 4096
+)
+
+// TextLength returns the length Telegram counts for the text rendered from nodes: the UTF-16 length of its
+// text without markup and surrounding whitespace, which is the same in every parse mode.
+func TextLength(nodes []Node) int {
+	text, _ := Entities(nodes)
+	return utf16Length(text)
+}
+
+// Caption returns the caption of a media toSend, or the caption of the first item of an album.
+func Caption(toSend any) Text {
+	switch v := toSend.(type) {
+	case Captioned:
+		return v.Caption
+	case telebot.Album:
+		if len(v) > 0 {
+			if item, ok := v[0].(AlbumItem); ok {
+				return item.Caption
+			}
+		}
+	}
+	return Text{}
+}
+
+// WithCaption returns a copy of a media toSend with its caption, or the caption of the first item of an album,
+// replaced by caption.
+func WithCaption(toSend any, caption Text) any {
+	switch v := toSend.(type) {
+	case Captioned:
+		v.Caption = caption
+		return v
+	case telebot.Album:
+		if len(v) > 0 {
+			if item, ok := v[0].(AlbumItem); ok {
+				album := slices.Clone(v)
+				item.Caption = caption
+				album[0] = item
+				return album
+			}
+		}
+	}
+	return toSend
+}
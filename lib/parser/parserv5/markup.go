@@ -0,0 +1,79 @@
+package parserv5
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// JumpText is the text of the button linking back to the original Discord message.
+const JumpText = "Open in Discord"
+
+// ReplyMarkup converts Discord message components into a Telegram inline keyboard.
+// Link buttons are kept as URL buttons, while interactive components that cannot work in Telegram
+// are replaced by a single button jumping to the message at url.
+// If jump is not empty, a button with that text linking to url is always appended.
+// It returns nil if there are no buttons to show.
+func ReplyMarkup(components []discordgo.MessageComponent, url string, jump string) *telebot.ReplyMarkup {
+	var (
+		keyboard    [][]telebot.InlineButton
+		interactive bool
+	)
+	var walk func(components []discordgo.MessageComponent)
+	walk = func(components []discordgo.MessageComponent) {
+		for _, component := range components {
+			switch c := component.(type) {
+			case *discordgo.ActionsRow:
+				var row []telebot.InlineButton
+				for _, child := range c.Components {
+					if button, ok := linkButton(child); ok {
+						row = append(row, button)
+					} else {
+						interactive = true
+					}
+				}
+				if len(row) > 0 {
+					keyboard = append(keyboard, row)
+				}
+			case *discordgo.Section:
+				if button, ok := linkButton(c.Accessory); ok {
+					keyboard = append(keyboard, []telebot.InlineButton{button})
+				} else if _, ok := c.Accessory.(*discordgo.Button); ok {
+					interactive = true
+				}
+			case *discordgo.Container:
+				walk(c.Components)
+			}
+		}
+	}
+	walk(components)
+
+	if jump == "" && interactive {
+		jump = JumpText
+	}
+	if jump != "" && url != "" {
+		keyboard = append(keyboard, []telebot.InlineButton{{Text: jump, URL: url}})
+	}
+	if len(keyboard) == 0 {
+		return nil
+	}
+	return &telebot.ReplyMarkup{InlineKeyboard: keyboard}
+}
+
+func linkButton(component discordgo.MessageComponent) (telebot.InlineButton, bool) {
+	button, ok := component.(*discordgo.Button)
+	if !ok || button.Style != discordgo.LinkButton || button.URL == "" {
+		return telebot.InlineButton{}, false
+	}
+	text := button.Label
+	if button.Emoji != nil && button.Emoji.ID == "" && button.Emoji.Name != "" {
+		if text == "" {
+			text = button.Emoji.Name
+		} else {
+			text = button.Emoji.Name + " " + text
+		}
+	}
+	if text == "" {
+		text = "Link"
+	}
+	return telebot.InlineButton{Text: text, URL: button.URL}, true
+}
@@ -0,0 +1,144 @@
+package parserv5
+
+import (
+	"bytes"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// Captioned is media with a formatted caption, which is set on the media when it is rendered.
+type Captioned struct {
+	telebot.Media
+	Caption Text
+}
+
+func (c Captioned) String() string {
+	return c.Caption.String()
+}
+
+// Source returns the Discord markdown the caption was parsed from.
+func (c Captioned) Source() string {
+	return c.Caption.Source()
+}
+
+// AlbumItem is an item of a telebot.Album with a formatted caption, which is set on the item when it is rendered.
+type AlbumItem struct {
+	telebot.Inputtable
+	Caption Text
+}
+
+func (a AlbumItem) String() string {
+	return a.Caption.String()
+}
+
+// Source returns the Discord markdown the caption was parsed from.
+func (a AlbumItem) Source() string {
+	return a.Caption.Source()
+}
+
+// mediaKind returns the Telegram media type attachment should be sent as, based on its
+// content type and falling back to its file extension.
+func mediaKind(m *discordgo.Message, attachment *discordgo.MessageAttachment) string {
+	if m.Flags&discordgo.MessageFlagsIsVoiceMessage != 0 {
+		return "voice"
+	}
+	contentType := attachment.ContentType
+	extension := strings.ToLower(path.Ext(attachment.Filename))
+	switch {
+	case contentType == "image/gif" || extension == ".gif":
+		return "animation"
+	case isImage(contentType):
+		return "photo"
+	case strings.HasPrefix(contentType, "video/"),
+		extension == ".mp4", extension == ".mov", extension == ".webm", extension == ".mkv":
+		return "video"
+	case strings.HasPrefix(contentType, "audio/"),
+		extension == ".mp3", extension == ".ogg", extension == ".wav", extension == ".flac", extension == ".m4a":
+		return "audio"
+	default:
+		return "document"
+	}
+}
+
+// attachmentSendable wraps the downloaded data of attachment in the Telegram media type matching mediaKind.
+func attachmentSendable(m *discordgo.Message, attachment *discordgo.MessageAttachment, data []byte) telebot.Media {
+	file := telebot.FromReader(bytes.NewReader(data))
+	duration := int(math.Round(attachment.DurationSecs))
+	switch mediaKind(m, attachment) {
+	case "voice":
+		return &telebot.Voice{
+			File:     file,
+			Duration: duration,
+			MIME:     attachment.ContentType,
+		}
+	case "animation":
+		return &telebot.Animation{
+			File:       file,
+			Width:      attachment.Width,
+			Height:     attachment.Height,
+			FileName:   attachment.Filename,
+			HasSpoiler: isSpoiler(attachment),
+		}
+	case "photo":
+		if photo, err := photoSendable(attachment.URL, isSpoiler(attachment)); err == nil {
+			if document, ok := photo.(*telebot.Document); ok {
+				document.FileName = attachment.Filename
+			}
+			return photo
+		}
+		return &telebot.Document{
+			File:     file,
+			FileName: attachment.Filename,
+		}
+	case "video":
+		return &telebot.Video{
+			File:       file,
+			Width:      attachment.Width,
+			Height:     attachment.Height,
+			Duration:   duration,
+			Streaming:  true,
+			MIME:       attachment.ContentType,
+			FileName:   attachment.Filename,
+			HasSpoiler: isSpoiler(attachment),
+		}
+	case "audio":
+		return &telebot.Audio{
+			File:     file,
+			Duration: duration,
+			MIME:     attachment.ContentType,
+			FileName: attachment.Filename,
+		}
+	default:
+		return &telebot.Document{
+			File:     file,
+			FileName: attachment.Filename,
+		}
+	}
+}
+
+// albumItem converts attachment into an item Telegram accepts in a media group.
+// Animations are sent as documents and voice messages as audio, as media groups cannot hold them.
+// Documents cannot be blurred, so spoilered animations are sent as a photo of their first frame instead.
+func albumItem(m *discordgo.Message, attachment *discordgo.MessageAttachment, data []byte) telebot.Inputtable {
+	switch media := attachmentSendable(m, attachment, data).(type) {
+	case *telebot.Animation:
+		if media.HasSpoiler {
+			if photo, err := photoSendable(attachment.URL, true); err == nil {
+				if photo, ok := photo.(*telebot.Photo); ok {
+					return photo
+				}
+			}
+		}
+		return &telebot.Document{File: media.File, FileName: attachment.Filename}
+	case *telebot.Voice:
+		return &telebot.Audio{File: media.File, Duration: media.Duration, MIME: media.MIME, FileName: attachment.Filename}
+	case telebot.Inputtable:
+		return media
+	default:
+		return &telebot.Document{File: *media.MediaFile(), FileName: attachment.Filename}
+	}
+}
@@ -33,21 +33,26 @@ type FormattingNode struct {
 }
 
 func (n *FormattingNode) String() string {
-	var inner strings.Builder
-	for _, child := range n.Children {
-		inner.WriteString(child.String())
+	inner := renderNodes(n.Children, 0)
+	if inner == "" {
+		// Telegram has no empty entities: Discord shows the tokens as they are.
+		return escapeTelegram(n.Format + n.Format)
 	}
 	// Map Discord tokens to Telegram MarkdownV2 equivalents.
+	token := n.Format
 	switch n.Format {
 	case "~~":
-		return "~" + inner.String() + "~"
+		token = "~"
 	case "**":
-		return "*" + inner.String() + "*"
+		token = "*"
 	case "*":
-		return "_" + inner.String() + "_"
-	default:
-		return n.Format + inner.String() + n.Format
+		token = "_"
+	}
+	var sb strings.Builder
+	for _, s := range []string{token, inner, token} {
+		appendMarkdown(&sb, s)
 	}
+	return sb.String()
 }
 
 // CodeNode represents inline code.
@@ -68,9 +73,13 @@ func (n *CodeBlockNode) String() string {
 	if strings.TrimSpace(n.Text) == "" {
 		return ""
 	}
-	text := strings.ReplaceAll(n.Text, "\\", "\\\\")
-	text = strings.ReplaceAll(text, "`", "\\`")
-	return "```" + text + "```"
+	return "```" + escapeCode(n.Text) + "```"
+}
+
+// escapeCode escapes the characters Telegram MarkdownV2 requires to be escaped inside code.
+func escapeCode(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	return strings.ReplaceAll(text, "`", "\\`")
 }
 
 // QuoteBlockNode represents a quote block.
@@ -79,11 +88,7 @@ type QuoteBlockNode struct {
 }
 
 func (n *QuoteBlockNode) String() string {
-	var inner strings.Builder
-	for _, child := range n.Children {
-		inner.WriteString(child.String())
-	}
-	return ">" + inner.String() + "\n"
+	return ">" + renderNodes(n.Children, 0) + "\n"
 }
 
 type HeaderNode struct {
@@ -92,32 +97,48 @@ type HeaderNode struct {
 }
 
 func (n *HeaderNode) String() string {
-	var inner strings.Builder
-	for _, child := range n.Children {
-		inner.WriteString(child.String())
-	}
+	inner := renderNodes(n.Children, 0)
 	switch n.Level {
 	case 1:
-		return ">*" + strings.Trim(inner.String(), "*") + "*\n"
+		return ">*" + trimBold(inner) + "*\n"
 	case 2:
-		return ">" + inner.String() + "\n"
+		return ">" + inner + "\n"
 	case 3:
-		return "*" + strings.Trim(inner.String(), "*") + "*\n"
+		return "*" + trimBold(inner) + "*\n"
 	default:
-		return inner.String()
+		return inner
+	}
+}
+
+// trimBold trims the bold tokens around the rendered text of a header, which is bold as a whole,
+// leaving escaped asterisks.
+func trimBold(s string) string {
+	s = strings.TrimLeft(s, "*")
+	for strings.HasSuffix(s, "*") && !isEscaped(s, len(s)-1) {
+		s = s[:len(s)-1]
 	}
+	return s
 }
 
 // LinkNode represents an inline link [text](url).
 type LinkNode struct {
 	Text string
 	URL  string
+	// Children is the formatted text of the link, if it is not plain Text.
+	Children []Node
 }
 
 func (n *LinkNode) String() string {
-	url := strings.ReplaceAll(n.URL, "\\", "\\\\")
-	url = strings.ReplaceAll(url, ")", "\\)")
-	return fmt.Sprintf("[%s](%s)", escapeTelegram(n.Text), url)
+	if n.Children != nil {
+		return fmt.Sprintf("[%s](%s)", renderNodes(n.Children, len(n.Text)), escapeURL(n.URL))
+	}
+	return fmt.Sprintf("[%s](%s)", escapeTelegram(n.Text), escapeURL(n.URL))
+}
+
+// escapeURL escapes the characters Telegram MarkdownV2 requires to be escaped inside a link URL.
+func escapeURL(url string) string {
+	url = strings.ReplaceAll(url, "\\", "\\\\")
+	return strings.ReplaceAll(url, ")", "\\)")
 }
 
 // MentionNode represents a mention (user, channel, role).
@@ -136,40 +157,43 @@ type TimestampNode struct {
 }
 
 func (n *TimestampNode) String() string {
+	primary, secondary := n.parts()
+	if secondary == "" {
+		return "*" + escapeTelegram(primary) + "*"
+	}
+	return fmt.Sprintf(`*%s* \(%s\)`, escapeTelegram(primary), escapeTelegram(secondary))
+}
+
+// parts returns the time n is rendered as in bold, and the time shown in parentheses after it, if any.
+func (n *TimestampNode) parts() (string, string) {
 	utc := time.Unix(n.Timestamp, 0).UTC()
+	if n.Style == "R" {
+		return formatRelativeTime(utc), utc.Format("January 02, 2006 3:04 PM MST")
+	}
 	est, err := time.LoadLocation("America/New_York")
 	if err != nil {
-		return n.format(utc)
+		return n.format(utc), ""
 	}
-
-	return fmt.Sprintf(`*%s* \(%s\)`, n.format(utc), n.format(utc.In(est)))
+	return n.format(utc), n.format(utc.In(est))
 }
 
 func (n *TimestampNode) format(t time.Time) string {
-	var formatted string
 	switch n.Style {
 	case "t":
-		formatted = t.Format("3:04 PM MST")
+		return t.Format("3:04 PM MST")
 	case "T":
-		formatted = t.Format("3:04:05 PM MST")
+		return t.Format("3:04:05 PM MST")
 	case "d":
-		formatted = t.Format("02/01/2006")
+		return t.Format("02/01/2006")
 	case "D":
-		formatted = t.Format("January 02, 2006")
+		return t.Format("January 02, 2006")
 	case "f":
-		formatted = t.Format("January 02, 2006 3:04 PM MST")
+		return t.Format("January 02, 2006 3:04 PM MST")
 	case "F":
-		formatted = t.Format("Monday, January 02, 2006 3:04 PM MST")
-	case "R":
-		return formatRelativeFull(t)
+		return t.Format("Monday, January 02, 2006 3:04 PM MST")
 	default:
-		formatted = t.Format(time.RFC3339)
+		return t.Format(time.RFC3339)
 	}
-	return escapeTelegram(formatted)
-}
-
-func formatRelativeFull(t time.Time) string {
-	return fmt.Sprintf(`*%s* \(%s\)`, formatRelativeTime(t), escapeTelegram(t.Format("January 02, 2006 3:04 PM MST")))
 }
 
 func formatRelativeTime(t time.Time) string {
@@ -227,6 +251,26 @@ func escapeTelegram(text string) string {
 	return sb.String()
 }
 
+// appendMarkdown appends s to sb. An italic token ending sb and an underscore starting s are separated by
+// an empty bold entity, as Telegram reads underscores greedily as underline tokens, and a quote starting s
+// is escaped if sb does not end a line, as Telegram only quotes at the start of a line.
+func appendMarkdown(sb *strings.Builder, s string) {
+	written := sb.String()
+	trimmed := strings.TrimRight(written, "_")
+	// underscores is the number of underscores ending sb that are tokens.
+	underscores := len(written) - len(trimmed)
+	if underscores > 0 && isEscaped(written, len(trimmed)) {
+		underscores--
+	}
+	switch {
+	case strings.HasPrefix(s, "_") && underscores%2 == 1:
+		sb.WriteString("**")
+	case strings.HasPrefix(s, ">") && written != "" && !strings.HasSuffix(written, "\n"):
+		sb.WriteString("\\")
+	}
+	sb.WriteString(s)
+}
+
 // isEscaped returns true if the character at pos in text is escaped by an odd number of preceding backslashes.
 // Note: pos is a byte index.
 func isEscaped(text string, pos int) bool {
@@ -251,6 +295,23 @@ func findClosing(text string, start int, token string) int {
 	return -1
 }
 
+// findSingleClosing is like findClosing for a single-character token, skipping the doubled tokens
+// of the formatting it contains.
+func findSingleClosing(text string, start int, token string) int {
+	for j := start; j < len(text); {
+		if strings.HasPrefix(text[j:], token+token) && !isEscaped(text, j) {
+			j += 2 * len(token)
+			continue
+		}
+		if strings.HasPrefix(text[j:], token) && !isEscaped(text, j) {
+			return j
+		}
+		_, size := utf8.DecodeRuneInString(text[j:])
+		j += size
+	}
+	return -1
+}
+
 // buildAST parses the input string into an AST using UTF-8 aware decoding.
 func buildAST(input string) []Node {
 	var (
@@ -268,7 +329,7 @@ func buildAST(input string) []Node {
 			continue
 		}
 
-		// Handle markers such as [[TIMESTAMP:...]], [[MENTION:...]], [[CHANNEL:...]]
+		// Handle markers such as [[TIMESTAMP:...]], [[MENTION:...]], [[EMOJI:...]], [[CHANNEL:...]]
 		if strings.HasPrefix(input[i:], "[[TIMESTAMP:") {
 			end := findClosing(input, i+len("[[TIMESTAMP:"), "]]")
 			if end != -1 {
@@ -294,6 +355,18 @@ func buildAST(input string) []Node {
 				continue
 			}
 		}
+		if strings.HasPrefix(input[i:], "[[EMOJI:") {
+			end := findClosing(input, i+len("[[EMOJI:"), "]]")
+			if end != -1 {
+				marker := input[i : end+len("]]")]
+				parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(marker, "[[EMOJI:"), "]]"), ":")
+				if len(parts) == 3 {
+					nodes = append(nodes, &EmojiNode{Name: parts[1], ID: parts[2], Animated: parts[0] == "a"})
+					i = end + len("]]")
+					continue
+				}
+			}
+		}
 		if strings.HasPrefix(input[i:], "[[CHANNEL:") {
 			end := findClosing(input, i+len("[[CHANNEL:"), "]]")
 			if end != -1 {
@@ -400,7 +473,7 @@ func buildAST(input string) []Node {
 		// Single-character formatting tokens: * and _
 		if r == '*' || r == '_' {
 			token := input[i : i+size]
-			end := findClosing(input, i+size, token)
+			end := findSingleClosing(input, i+size, token)
 			if end != -1 {
 				innerContent := input[i+size : end]
 				children := buildAST(innerContent)
@@ -4,13 +4,40 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/charmbracelet/log"
 )
 
-func Parser(s *discordgo.Session, m *discordgo.Message) func(string) string {
-	return func(text string) string {
-		return Parse(s, m, text)
+// Options configures how Discord messages are converted for Telegram.
+type Options struct {
+	// Emoji configures the rendering of custom emoji.
+	Emoji EmojiOptions
+	// Files is the file server used for oversized attachments. If it is not configured,
+	// oversized attachments link to the Discord CDN instead.
+	Files FileServer
+	// AltTextFormat is the Discord markdown line appended to a caption for an attachment with alt text,
+	// where %s is replaced by the description. Alt text is left out if it is empty.
+	AltTextFormat string
+	// Logger reports failures that do not keep a message from being sent.
+	// If it is not set, the default logger is used.
+	Logger *log.Logger
+}
+
+func (o Options) logger() *log.Logger {
+	if o.Logger == nil {
+		return log.Default()
+	}
+	return o.Logger
+}
+
+// Parser returns a function parsing the Discord markdown texts of m like ParseText, rendering custom emoji
+// as configured by o.
+func Parser(s *discordgo.Session, m *discordgo.Message, o Options) func(string) Text {
+	return func(text string) Text {
+		return parseText(s, m, text, o.Emoji)
 	}
 }
 
@@ -18,36 +45,47 @@ func Parser(s *discordgo.Session, m *discordgo.Message) func(string) string {
 // markers (timestamps, mentions) with temporary markers, builds the AST, then renders it.
 func Parse(s *discordgo.Session, m *discordgo.Message, text string) string {
 	// Preprocess: replace Discord timestamps and mentions with marker strings.
-	text = preprocess(s, m, text)
+	text = preprocess(s, m, text, EmojiOptions{})
 	// Build AST from the resulting text.
 	if !strings.HasSuffix(text, "\n") {
 		text = fmt.Sprintf("%s\n", text)
 	}
 	nodes := buildAST(text)
 	// Render AST back into a Telegram MarkdownV2 string.
-	return strings.TrimSpace(renderNodes(nodes, len(text)))
+	return trimMarkdown(renderNodes(nodes, len(text)))
 }
 
 func AST(text string) []Node {
-	text = preprocess(nil, nil, text)
+	text = preprocess(nil, nil, text, EmojiOptions{})
 	return buildAST(text)
 }
 
-// renderNodes concatenates the rendered output of all AST nodes.
+// renderNodes concatenates the rendered output of all AST nodes with appendMarkdown.
 func renderNodes(nodes []Node, length int) string {
 	var sb strings.Builder
 	sb.Grow(length)
 	for _, n := range nodes {
-		sb.WriteString(n.String())
+		appendMarkdown(&sb, n.String())
 	}
 	return sb.String()
 }
 
+// trimMarkdown trims the spaces around rendered MarkdownV2, keeping an escaped space that ends it.
+func trimMarkdown(s string) string {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	trimmed := strings.TrimRightFunc(s, unicode.IsSpace)
+	if len(trimmed) < len(s) && isEscaped(s, len(trimmed)) {
+		_, size := utf8.DecodeRuneInString(s[len(trimmed):])
+		trimmed = s[:len(trimmed)+size]
+	}
+	return trimmed
+}
+
 // preprocess converts tokens like <t:...> and <@...> into unique markers.
-func preprocess(s *discordgo.Session, m *discordgo.Message, text string) string {
+func preprocess(s *discordgo.Session, m *discordgo.Message, text string, emoji EmojiOptions) string {
 	text = parseTimestampsToString(text)
 	text = replaceMentionsToString(s, m, text)
-	text = removeCustomEmojis(text)
+	text = replaceEmojisToString(text, emoji)
 	return text
 }
 
@@ -68,7 +106,7 @@ var (
 	userRe    = regexp.MustCompile(`<@!?(?P<id>\d+)>`)
 	roleRe    = regexp.MustCompile(`<@&(?P<id>\d+)>`)
 	channelRe = regexp.MustCompile(`<#(?P<id>\d+)>`)
-	emojiRe   = regexp.MustCompile(`<:(?P<name>\w+):(?P<id>\d+)>`)
+	emojiRe   = regexp.MustCompile(`<(?P<animated>a?):(?P<name>\w+):(?P<id>\d+)>`)
 )
 
 func replaceMentionsToString(s *discordgo.Session, m *discordgo.Message, text string) string {
@@ -121,6 +159,15 @@ func replaceMentionsToString(s *discordgo.Session, m *discordgo.Message, text st
 	return text
 }
 
-func removeCustomEmojis(text string) string {
-	return emojiRe.ReplaceAllString(text, "")
+// Replace Discord custom emoji with markers of the form [[EMOJI:animated:name:id]],
+// or with the Unicode emoji they are mapped to unless emoji renders them by name.
+func replaceEmojisToString(text string, emoji EmojiOptions) string {
+	return emojiRe.ReplaceAllStringFunc(text, func(match string) string {
+		if emoji.Mode != EmojiModeName {
+			if unicode, ok := emoji.Mapping[emojiRe.FindStringSubmatch(match)[emojiRe.SubexpIndex("name")]]; ok {
+				return unicode
+			}
+		}
+		return emojiRe.ReplaceAllString(match, "[[EMOJI:${animated}:${name}:${id}]]")
+	})
 }
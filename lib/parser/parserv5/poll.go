@@ -0,0 +1,87 @@
+package parserv5
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// Telegram limits for native polls.
+const (
+	maxPollQuestion = 300
+	maxPollOption   = 100
+	minPollOptions  = 2
+	maxPollOptions  = 10
+)
+
+// NativePoll converts a Discord poll into a native Telegram poll.
+// It returns nil when the poll cannot be represented within Telegram's option limits.
+func NativePoll(poll *discordgo.Poll) *telebot.Poll {
+	if poll == nil || len(poll.Answers) < minPollOptions || len(poll.Answers) > maxPollOptions {
+		return nil
+	}
+
+	native := &telebot.Poll{
+		Type:            telebot.PollRegular,
+		Question:        truncate(pollMediaText(&poll.Question), maxPollQuestion),
+		MultipleAnswers: poll.AllowMultiselect,
+		Anonymous:       true,
+	}
+	for _, answer := range poll.Answers {
+		native.AddOptions(truncate(pollMediaText(answer.Media), maxPollOption))
+	}
+	return native
+}
+
+// PollResults renders the final results of a Discord poll as a Discord markdown summary,
+// to be passed through the parser before sending.
+func PollResults(poll *discordgo.Poll) string {
+	counts := make(map[int]int)
+	var total, highest int
+	if poll.Results != nil {
+		for _, count := range poll.Results.AnswerCounts {
+			counts[count.ID] = count.Count
+			total += count.Count
+			highest = max(highest, count.Count)
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📊 **Poll results: %s**\n", pollMediaText(&poll.Question)))
+	for _, answer := range poll.Answers {
+		count := counts[answer.AnswerID]
+		var percent int
+		if total > 0 {
+			percent = count * 100 / total
+		}
+		marker := "▫️"
+		if count > 0 && count == highest {
+			marker = "🏆"
+		}
+		text.WriteString(fmt.Sprintf("%s %s: **%d** (%d%%)\n", marker, pollMediaText(answer.Media), count, percent))
+	}
+	text.WriteString(fmt.Sprintf("_%d votes in total_", total))
+	return text.String()
+}
+
+func pollMediaText(media *discordgo.PollMedia) string {
+	if media == nil {
+		return ""
+	}
+	// Custom emojis have no Telegram equivalent, only unicode emojis are kept.
+	if media.Emoji != nil && media.Emoji.ID == "" && media.Emoji.Name != "" {
+		return media.Emoji.Name + " " + media.Text
+	}
+	return media.Text
+}
+
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,75 @@
+package parserv5
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// Preview is a text message previewing a chosen URL rather than the first link of its text.
+// It is rendered into a wrapper.Preview.
+type Preview struct {
+	Text    Text
+	Options telebot.PreviewOptions
+}
+
+// String renders the text of p as Telegram MarkdownV2.
+func (p Preview) String() string {
+	return p.Text.String()
+}
+
+// Source returns the Discord markdown the text of p was parsed from.
+func (p Preview) Source() string {
+	return p.Text.Source()
+}
+
+// embedMediaSendable forwards the first gifv or video embed of m by its type: gifv embeds become an animation
+// of the embed video, and video embeds become a text message previewing the embed URL.
+// It returns false if m has no such embed, leaving the thumbnail to be sent as a photo instead.
+func embedMediaSendable(m *discordgo.Message, p parser) (any, bool, error) {
+	for _, embed := range m.Embeds {
+		switch {
+		case embed.Type == discordgo.EmbedTypeGifv && embed.Video != nil && embed.Video.URL != "":
+			reader, err := lib.DefaultCache.Get(embed.Video.URL)
+			if err != nil {
+				return nil, false, err
+			}
+			animation := &telebot.Animation{
+				File:     telebot.FromReader(bytes.NewReader(reader)),
+				Width:    embed.Video.Width,
+				Height:   embed.Video.Height,
+				FileName: path.Base(strings.Split(embed.Video.URL, "?")[0]),
+			}
+			return Captioned{Media: animation, Caption: p(strings.TrimSpace(strings.Replace(m.Content, embed.URL, "", 1)))}, true, nil
+		case embed.Type == discordgo.EmbedTypeVideo && embed.URL != "":
+			text := p(m.Content)
+			if !strings.Contains(m.Content, embed.URL) {
+				text = Join("\n", text, embedText(embed, p))
+			}
+			return Preview{
+				Text: text,
+				Options: telebot.PreviewOptions{
+					URL:        embed.URL,
+					LargeMedia: true,
+					AboveText:  true,
+				},
+			}, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// embedMediaURL returns the media embedMediaSendable forwards for m, if any.
+func embedMediaURL(m *discordgo.Message) string {
+	for _, embed := range m.Embeds {
+		if embed.Type == discordgo.EmbedTypeGifv && embed.Video != nil && embed.Video.URL != "" {
+			return embed.Video.URL
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package parserv5
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// maxQuoteExcerpt is the number of characters of the replied-to message shown in a quote.
+const maxQuoteExcerpt = 100
+
+// Quote renders a short excerpt of the replied-to message as a blockquote.
+// It is used when the message being replied to was never forwarded to Telegram.
+func Quote(s *discordgo.Session, referenced *discordgo.Message, o Options) Text {
+	author := "unknown"
+	if user := lib.GetUser(referenced); user != nil && user.DisplayName() != "" {
+		author = user.DisplayName()
+	}
+	p := Parser(s, referenced, o)
+	return Join(" ", p(fmt.Sprintf("**%s**:", lib.EscapeMarkdown(author))), excerpt(referenced, p)).quote()
+}
+
+// excerpt returns the first line of m, or a placeholder for what m holds.
+func excerpt(m *discordgo.Message, p parser) Text {
+	for line := range strings.Lines(m.Content) {
+		if line = strings.TrimSpace(line); line != "" {
+			return shorten(p(line), maxQuoteExcerpt)
+		}
+	}
+	switch {
+	case m.Poll != nil:
+		return shorten(p(m.Poll.Question.Text), maxQuoteExcerpt)
+	case len(m.Embeds) > 0 && m.Embeds[0].Title != "":
+		return shorten(p(m.Embeds[0].Title), maxQuoteExcerpt)
+	case len(m.Attachments) > 0:
+		return p("_attachment_")
+	case len(m.StickerItems) > 0:
+		return p("_sticker_")
+	case len(m.Embeds) > 0:
+		return p("_embed_")
+	default:
+		return p("_message_")
+	}
+}
+
+// shorten cuts t to at most limit characters, ending with an ellipsis. It is cut along its AST with SplitText,
+// so that formatting, links, mentions and custom emoji are never cut in the middle.
+func shorten(t Text, limit int) Text {
+	if t.Len() <= limit {
+		return t
+	}
+	return Join("", SplitText(t, limit-1)[0], Plain("…"))
+}
+
+// WithPrefix prepends prefix on its own line to the text or caption of toSend, or as plain text
+// to the question of a poll, which has no caption and no formatting.
+func WithPrefix(toSend any, prefix Text) any {
+	if prefix.IsEmpty() {
+		return toSend
+	}
+	switch v := toSend.(type) {
+	case Text:
+		return Join("\n", prefix, v)
+	case Preview:
+		v.Text = Join("\n", prefix, v.Text)
+		return v
+	case Parts:
+		parts := slices.Clone(v)
+		parts[0] = Join("\n", prefix, parts[0])
+		return parts
+	case *telebot.Poll:
+		poll := *v
+		label, _ := Entities(prefix.Nodes)
+		label = strings.TrimSpace(label) + "\n"
+		poll.Question = truncate(label+truncate(poll.Question, max(maxPollQuestion-utf8.RuneCountInString(label), 1)), maxPollQuestion)
+		return &poll
+	}
+	return WithCaption(toSend, Join("\n", prefix, Caption(toSend)))
+}
@@ -0,0 +1,112 @@
+package parserv5
+
+import (
+	"strings"
+
+	"telegram-discord/lib/wrapper"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Renderer selects the parse mode the Text of a message is rendered in when it is sent to Telegram.
+type Renderer string
+
+const (
+	// RendererMarkdownV2 renders Text as MarkdownV2.
+	RendererMarkdownV2 Renderer = "markdownv2"
+	// RendererEntities renders Text as plain text formatted by message entities without a parse mode,
+	// so that formatting can never fail to parse.
+	RendererEntities Renderer = "entities"
+	// RendererHTML renders Text as Telegram HTML, which needs less escaping than MarkdownV2.
+	RendererHTML Renderer = "html"
+)
+
+// Render renders the Text of toSend, a Text, Preview, Captioned or album of AlbumItem, into what telebot sends.
+// It returns the rendered toSend and the options to send it with, leaving the arguments untouched.
+// Anything else is returned as is.
+func (r Renderer) Render(toSend any, options *telebot.SendOptions) (any, *telebot.SendOptions) {
+	switch v := toSend.(type) {
+	case Text:
+		return r.text(v, options)
+	case Preview:
+		text, rendered := r.text(v.Text, options)
+		return wrapper.Preview{Text: text, Options: v.Options}, rendered
+	case Captioned:
+		caption, rendered := r.text(v.Caption, options)
+		return withCaption(v.Media, caption), rendered
+	case telebot.Album:
+		_, rendered := r.text(Text{}, options)
+		album := make(telebot.Album, len(v))
+		for i, item := range v {
+			captioned, ok := item.(AlbumItem)
+			if !ok {
+				album[i] = item
+				continue
+			}
+			caption, captionOptions := r.text(captioned.Caption, options)
+			media := withCaption(captioned.Inputtable, caption).(telebot.Inputtable)
+			if r == RendererEntities {
+				// telebot applies the entities of the options to every item, so each item carries its own instead.
+				media = wrapper.Captioned{Inputtable: media, Entities: captionOptions.Entities}
+			}
+			album[i] = media
+		}
+		return album, rendered
+	default:
+		return toSend, options
+	}
+}
+
+// text renders t in the parse mode of r, returning it with the options to send it with.
+func (r Renderer) text(t Text, options *telebot.SendOptions) (string, *telebot.SendOptions) {
+	var rendered telebot.SendOptions
+	if options != nil {
+		rendered = *options
+	}
+	rendered.Entities = nil
+	switch r {
+	case RendererEntities:
+		rendered.ParseMode = telebot.ModeDefault
+		text, entities := Entities(t.Nodes)
+		rendered.Entities = entities
+		return text, &rendered
+	case RendererHTML:
+		rendered.ParseMode = telebot.ModeHTML
+		return strings.TrimSpace(HTML(t.Nodes)), &rendered
+	default:
+		rendered.ParseMode = telebot.ModeMarkdownV2
+		return t.String(), &rendered
+	}
+}
+
+// withCaption returns a copy of media with caption, or media itself if it has no caption.
+func withCaption(media telebot.Media, caption string) telebot.Media {
+	switch v := media.(type) {
+	case *telebot.Photo:
+		copied := *v
+		copied.Caption = caption
+		return &copied
+	case *telebot.Document:
+		copied := *v
+		copied.Caption = caption
+		return &copied
+	case *telebot.Animation:
+		copied := *v
+		copied.Caption = caption
+		return &copied
+	case *telebot.Video:
+		copied := *v
+		copied.Caption = caption
+		return &copied
+	case *telebot.Audio:
+		copied := *v
+		copied.Caption = caption
+		return &copied
+	case *telebot.Voice:
+		copied := *v
+		copied.Caption = caption
+		return &copied
+	default:
+		return media
+	}
+}
@@ -1,7 +1,6 @@
 package parserv5
 
 import (
-	"bytes"
 	"fmt"
 	"regexp"
 	"strings"
@@ -12,18 +11,23 @@ import (
 	"gopkg.in/telebot.v4"
 )
 
-type parser = func(text string) string
+type parser = func(text string) Text
 
-func Sendable(s *discordgo.Session, m *discordgo.Message, p parser) (any, error) {
-	if p == nil {
-		p = Parser(s, m)
-	}
+// Sendable converts m into what is sent to Telegram. descriptions are the alt texts of its attachments
+// keyed by attachment ID, as returned by lib.AttachmentDescriptions.
+func Sendable(s *discordgo.Session, m *discordgo.Message, descriptions map[string]string, o Options) (any, error) {
+	p := Parser(s, m, o)
 
 	if m.Poll != nil {
 		return p(m.Poll.Question.Text), nil
 	}
 
-	if user := lib.GetUser(m); user != nil && !user.Bot {
+	if emoji, ok := onlyEmoji(m); ok && o.Emoji.Mode == EmojiModeImage {
+		return emojiSendable(emoji, emojiAuthor(m, p))
+	}
+
+	// Forwards name their author in the attribution added by the bot instead.
+	if user := lib.GetUser(m); user != nil && !user.Bot && !isForward(m) {
 		displayName := user.DisplayName()
 		mentioned, err := regexp.Compile(fmt.Sprintf(`^\*%s\*: `, displayName))
 		if err == nil && !mentioned.MatchString(m.Content) && displayName != "" {
@@ -31,86 +35,196 @@ func Sendable(s *discordgo.Session, m *discordgo.Message, p parser) (any, error)
 		}
 	}
 
+	if len(m.Attachments) > 1 {
+		return albumSendable(m, descriptions, p, o)
+	}
+
 	if len(m.Embeds) > 0 {
-		for _, embed := range m.Embeds {
-			if embed.Image != nil {
-				reader, err := lib.DefaultCache.Get(embed.Image.URL)
-				if err != nil {
-					return nil, err
-				}
-				return &telebot.Photo{
-					File:    telebot.FromReader(bytes.NewReader(reader)),
-					Caption: formatEmbedToMarkdownV2(embed, p),
-				}, nil
-			}
-			if embed.Thumbnail != nil {
-				reader, err := lib.DefaultCache.Get(embed.Thumbnail.URL)
+		if toSend, ok, err := embedMediaSendable(m, p); ok || err != nil {
+			return toSend, err
+		}
+		for _, gallery := range embedGalleries(m.Embeds) {
+			images := galleryImages(gallery)
+			switch len(images) {
+			case 0:
+				continue
+			case 1:
+				media, err := photoSendable(images[0], false)
 				if err != nil {
 					return nil, err
 				}
-				return &telebot.Photo{
-					File:    telebot.FromReader(bytes.NewReader(reader)),
-					Caption: formatEmbedToMarkdownV2(embed, p),
-				}, nil
+				return Captioned{Media: media, Caption: embedText(gallery[0], p)}, nil
+			default:
+				return galleryAlbum(images, embedText(gallery[0], p))
 			}
 		}
 
-		return formatEmbedsToMarkdownV2(m.Embeds, p), nil
+		if text := embedsText(m.Embeds, p); text.Len() <= MaxText {
+			return text, nil
+		}
+		return Parts(embedParts(m.Embeds, p, MaxText)), nil
 	}
 
 	for _, attachment := range m.Attachments {
+		content := withLine(m.Content, altText(descriptions, attachment, o))
+		if tooLarge(attachment) {
+			return p(withLinks(content, []string{downloadLink(attachment, o)})), nil
+		}
 		reader, err := lib.DefaultCache.Get(attachment.URL)
 		if err != nil {
-			return nil, err
+			return p(withLinks(content, []string{downloadLink(attachment, o)})), nil
 		}
 
-		if isImage(attachment.ContentType) {
-			return &telebot.Photo{
-				File:    telebot.FromReader(bytes.NewReader(reader)),
-				Caption: p(m.Content),
-			}, nil
+		return Captioned{Media: attachmentSendable(m, attachment, reader), Caption: p(content)}, nil
+	}
+
+	if len(m.StickerItems) > 0 {
+		return stickerSendable(m.StickerItems[0], m.Content, p)
+	}
+
+	text := p(m.Content)
+	if text.Len() <= MaxText {
+		return text, nil
+	}
+	return Parts(SplitText(text, MaxText)), nil
+}
+
+// albumSendable converts every attachment of m into a telebot.Album. Each item is captioned with its own
+// alt text, and the first item also with the content of m. Attachments that cannot be uploaded are linked
+// in the caption of the first item instead.
+func albumSendable(m *discordgo.Message, descriptions map[string]string, p parser, o Options) (any, error) {
+	var (
+		album    telebot.Album
+		links    []string
+		captions []string
+	)
+	for _, attachment := range m.Attachments {
+		if tooLarge(attachment) {
+			links = append(links, withLine(downloadLink(attachment, o), altText(descriptions, attachment, o)))
+			continue
 		}
+		reader, err := lib.DefaultCache.Get(attachment.URL)
+		if err != nil {
+			links = append(links, withLine(downloadLink(attachment, o), altText(descriptions, attachment, o)))
+			continue
+		}
+		album = append(album, albumItem(m, attachment, reader))
+		captions = append(captions, altText(descriptions, attachment, o))
+	}
+	if len(album) == 0 {
+		return p(withLinks(m.Content, links)), nil
+	}
+	captions[0] = withLinks(withLine(m.Content, captions[0]), links)
+	for i, item := range album {
+		album[i] = AlbumItem{Inputtable: item, Caption: p(captions[i])}
+	}
+	return album, nil
+}
+
+// SameMedia reports whether before and after would be forwarded with the same media,
+// following the same selection order as Sendable with o.
+func SameMedia(before, after *discordgo.Message, o Options) bool {
+	return mediaURL(before, o) == mediaURL(after, o)
+}
 
-		return &telebot.Document{
-			File:    telebot.FromReader(bytes.NewReader(reader)),
-			Caption: p(m.Content),
-		}, nil
+func mediaURL(m *discordgo.Message, o Options) string {
+	if m == nil || m.Poll != nil {
+		return ""
+	}
+	if len(m.Attachments) > 1 {
+		urls := make([]string, len(m.Attachments))
+		for i, attachment := range m.Attachments {
+			urls[i] = attachment.URL
+		}
+		return strings.Join(urls, "\n")
+	}
+	if emoji, ok := onlyEmoji(m); ok && o.Emoji.Mode == EmojiModeImage {
+		return emoji.URL()
+	}
+	if len(m.Embeds) > 0 {
+		if video := embedMediaURL(m); video != "" {
+			return video
+		}
+		for _, gallery := range embedGalleries(m.Embeds) {
+			if images := galleryImages(gallery); len(images) > 0 {
+				return strings.Join(images, "\n")
+			}
+		}
+		return ""
+	}
+	for _, attachment := range m.Attachments {
+		return attachment.URL
 	}
+	if len(m.StickerItems) > 0 {
+		return StickerURL(m.StickerItems[0])
+	}
+	return ""
+}
 
-	return p(m.Content), nil
+// embedText renders e the way Discord lays it out: the provider and author, the title linking
+// to the embed URL, the description, fields with consecutive inline fields sharing a line, and the footer
+// with the timestamp. The first line is prefixed with a square matching the embed color.
+// The same rendering is used for text messages and photo captions.
+func embedText(e *discordgo.MessageEmbed, p parser) Text {
+	return Join("\n", embedLines(e, p)...)
 }
 
-func formatEmbedToMarkdownV2(e *discordgo.MessageEmbed, p parser) string {
-	var text strings.Builder
+// embedLines renders the lines of e laid out by embedText.
+func embedLines(e *discordgo.MessageEmbed, p parser) []Text {
+	var lines []Text
+
+	var header []Text
+	if e.Provider != nil && e.Provider.Name != "" {
+		header = append(header, p(e.Provider.Name).link(e.Provider.URL).italic())
+	}
+	if e.Author != nil && e.Author.Name != "" {
+		header = append(header, p(e.Author.Name).link(e.Author.URL))
+	}
+	if len(header) > 0 {
+		lines = append(lines, Join(" · ", header...))
+	}
 
 	if e.Title != "" {
-		text.WriteString(fmt.Sprintf("*%s*\n", p(e.Title)))
+		lines = append(lines, p(e.Title).link(e.URL).bold())
 	}
 
 	if e.Description != "" {
-		text.WriteString(fmt.Sprintf("%s\n", p(e.Description)))
+		lines = append(lines, p(e.Description))
 	}
 
-	for _, field := range e.Fields {
-		text.WriteString(fmt.Sprintf("\n*%s:*\n%s\n", p(field.Name), p(field.Value)))
+	if fields := embedFields(e.Fields, p); !fields.IsEmpty() {
+		lines = append(lines, paragraph(fields))
 	}
 
-	if e.Footer != nil {
-		text.WriteString(fmt.Sprintf("\n_%s_", p(e.Footer.Text)))
+	var footer []Text
+	if e.Footer != nil && e.Footer.Text != "" {
+		footer = append(footer, p(e.Footer.Text).italic())
+	}
+	if timestamp := embedTimestamp(e.Timestamp); !timestamp.IsEmpty() {
+		footer = append(footer, timestamp)
+	}
+	if len(footer) > 0 {
+		lines = append(lines, paragraph(Join(" • ", footer...)))
 	}
 
-	return text.String()
+	if color := embedColor(e.Color); color != "" && len(lines) > 0 {
+		lines[0] = Join(" ", Plain(color), lines[0])
+	}
+	return lines
 }
 
-func formatEmbedsToMarkdownV2(embeds []*discordgo.MessageEmbed, p parser) string {
-	var text strings.Builder
+// embedsText renders embeds with embedText, separated by blank lines.
+func embedsText(embeds []*discordgo.MessageEmbed, p parser) Text {
+	texts := make([]Text, len(embeds))
 	for i, e := range embeds {
-		text.WriteString(formatEmbedToMarkdownV2(e, p))
-		if i < len(embeds)-1 {
-			text.WriteString("\n\n")
-		}
+		texts[i] = embedText(e, p)
 	}
-	return text.String()
+	return Join("\n\n", texts...)
+}
+
+// isForward reports whether m is a forward of another message.
+func isForward(m *discordgo.Message) bool {
+	return m.MessageReference != nil && m.MessageReference.Type == discordgo.MessageReferenceTypeForward
 }
 
 func isImage(contentType string) bool {
@@ -0,0 +1,265 @@
+package parserv5
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Parts is a text too long for a single Telegram message, split into the messages it is sent as, in order.
+type Parts []Text
+
+// SplitText splits t into parts of at most limit characters with Split. Every part keeps the source of t.
+func SplitText(t Text, limit int) []Text {
+	var parts []Text
+	for _, nodes := range Split(t.Nodes, limit) {
+		if part := (Text{Nodes: trimNodes(nodes), source: t.source}); !part.IsEmpty() {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// ReconcileParts turns the messages previously sent for the parts of a text into messages for parts:
+// edit is called on the previous messages with the part they now carry, send for the parts without a message,
+// and remove once with the previous messages no longer needed. It returns the messages now carrying parts,
+// in order, keeping the previous message of a part that failed to be edited and skipping those that failed
+// to be sent, along with the joined errors.
+func ReconcileParts[M any](
+	previous []M, parts []Text,
+	edit func(M, Text) (M, error), send func(Text) (M, error), remove func([]M),
+) ([]M, error) {
+	var (
+		current []M
+		errs    []error
+	)
+	for i, part := range parts {
+		if i < len(previous) {
+			edited, err := edit(previous[i], part)
+			if err != nil {
+				errs = append(errs, err)
+				edited = previous[i]
+			}
+			current = append(current, edited)
+			continue
+		}
+		sent, err := send(part)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		current = append(current, sent)
+	}
+	if len(previous) > len(parts) {
+		remove(previous[len(parts):])
+	}
+	return current, errors.Join(errs...)
+}
+
+// Split splits nodes into parts whose text is at most limit characters long, as counted by TextLength.
+// Parts end at paragraph breaks where possible, then at line breaks and sentence ends. Nodes that still
+// do not fit are split themselves, closing their formatting or code block at the end of a part and reopening
+// it at the start of the next, and only then are parts cut between words.
+func Split(nodes []Node, limit int) [][]Node {
+	var parts [][]Node
+	for len(nodes) > 0 {
+		if nodesLength(nodes) <= limit {
+			parts = append(parts, nodes)
+			break
+		}
+		head, tail := splitNodes(nodes, limit)
+		if len(head) == 0 {
+			// A single node longer than limit that cannot be split is sent on its own.
+			head, tail = nodes[:1], nodes[1:]
+		}
+		parts = append(parts, head)
+		nodes = tail
+	}
+	return parts
+}
+
+// splitNodes returns the longest head of nodes fitting in limit that ends at the best available boundary,
+// and the remaining nodes. The head is empty if nothing fits.
+func splitNodes(nodes []Node, limit int) ([]Node, []Node) {
+	// fit is the number of whole nodes fitting in limit.
+	fit, length := 0, 0
+	for fit < len(nodes) {
+		next := nodesLength(nodes[fit : fit+1])
+		if length+next > limit {
+			break
+		}
+		length += next
+		fit++
+	}
+
+	for _, boundary := range []func([]Node, int) bool{isParagraphEnd, isLineEnd, isSentenceEnd} {
+		if i := lastBoundary(nodes, fit, boundary); i > 0 {
+			return nodes[:i], nodes[i:]
+		}
+	}
+	if fit < len(nodes) {
+		if head, tail, ok := splitNode(nodes[fit], limit-length); ok {
+			return append(nodes[:fit:fit], head), append([]Node{tail}, nodes[fit+1:]...)
+		}
+	}
+	if i := lastBoundary(nodes, fit, isWordEnd); i > 0 {
+		return nodes[:i], nodes[i:]
+	}
+	return nodes[:fit], nodes[fit:]
+}
+
+// lastBoundary returns the largest index up to fit at which nodes can be split according to boundary,
+// or 0 if there is none.
+func lastBoundary(nodes []Node, fit int, boundary func([]Node, int) bool) int {
+	for i := fit; i > 0; i-- {
+		if boundary(nodes, i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// isParagraphEnd reports whether nodes[i-1] ends a paragraph: an empty line, or a line after a block.
+func isParagraphEnd(nodes []Node, i int) bool {
+	if !isText(nodes[i-1], "\n") || i < 2 {
+		return false
+	}
+	switch nodes[i-2].(type) {
+	case *CodeBlockNode, *QuoteBlockNode, *HeaderNode:
+		return true
+	}
+	return isText(nodes[i-2], "\n")
+}
+
+// isLineEnd reports whether nodes[i-1] ends a line.
+func isLineEnd(nodes []Node, i int) bool {
+	switch nodes[i-1].(type) {
+	case *CodeBlockNode, *QuoteBlockNode, *HeaderNode:
+		return true
+	}
+	return isText(nodes[i-1], "\n")
+}
+
+// isSentenceEnd reports whether nodes[i-1] is the space following the end of a sentence.
+func isSentenceEnd(nodes []Node, i int) bool {
+	if !isText(nodes[i-1], " ") || i < 2 {
+		return false
+	}
+	text, ok := nodes[i-2].(*TextNode)
+	return ok && strings.ContainsAny(text.Text[len(text.Text)-1:], ".!?")
+}
+
+// isWordEnd reports whether nodes[i-1] is a space.
+func isWordEnd(nodes []Node, i int) bool {
+	return isText(nodes[i-1], " ")
+}
+
+func isText(node Node, text string) bool {
+	n, ok := node.(*TextNode)
+	return ok && n.Text == text
+}
+
+// splitNode splits node in two, the first fitting in limit, repeating its formatting on both sides.
+func splitNode(node Node, limit int) (Node, Node, bool) {
+	switch n := node.(type) {
+	case *FormattingNode:
+		head, tail := splitNodes(n.Children, limit)
+		if len(head) == 0 || len(tail) == 0 {
+			return nil, nil, false
+		}
+		return &FormattingNode{Format: n.Format, Children: head}, &FormattingNode{Format: n.Format, Children: tail}, true
+	case *QuoteBlockNode:
+		head, tail := splitNodes(n.Children, limit)
+		if len(head) == 0 || len(tail) == 0 {
+			return nil, nil, false
+		}
+		return &QuoteBlockNode{Children: head}, &QuoteBlockNode{Children: tail}, true
+	case *CodeBlockNode:
+		return splitCodeBlock(n, limit)
+	default:
+		return nil, nil, false
+	}
+}
+
+// splitCodeBlock splits a code block between lines, repeating its language on both sides.
+func splitCodeBlock(n *CodeBlockNode, limit int) (Node, Node, bool) {
+	language, text := codeLanguage(n.Text)
+	if language != "" {
+		language += "\n"
+	}
+	lines := strings.SplitAfter(text, "\n")
+	var length int
+	for i, line := range lines {
+		length += utf16Length(line)
+		if length <= limit {
+			continue
+		}
+		if i == 0 {
+			return nil, nil, false
+		}
+		head := strings.Join(lines[:i], "")
+		tail := strings.Join(lines[i:], "")
+		return &CodeBlockNode{Text: language + head}, &CodeBlockNode{Text: language + tail}, true
+	}
+	return nil, nil, false
+}
+
+// embedParts renders embeds like embedsText, split into parts of at most limit characters.
+// Parts end between embeds or their lines where possible, and lines that do not fit in a part are split
+// along their AST with SplitText.
+func embedParts(embeds []*discordgo.MessageEmbed, p parser, limit int) []Text {
+	var lines []Text
+	for i, e := range embeds {
+		if i > 0 {
+			// An empty line separates embeds.
+			lines = append(lines, Text{})
+		}
+		for _, line := range embedLines(e, p) {
+			if line.Len() <= limit {
+				lines = append(lines, line)
+				continue
+			}
+			lines = append(lines, SplitText(line, limit)...)
+		}
+	}
+	return packParts(lines, limit)
+}
+
+// packParts joins consecutive lines into as few parts of at most limit characters as possible.
+func packParts(lines []Text, limit int) []Text {
+	var (
+		parts   []Text
+		current Text
+	)
+	flush := func() {
+		if current.Nodes = trimNodes(current.Nodes); !current.IsEmpty() {
+			parts = append(parts, current)
+		}
+		current = Text{}
+	}
+	for _, line := range lines {
+		next := appendLine(current, line)
+		if !current.IsEmpty() && next.Len() > limit {
+			flush()
+			next = line
+		}
+		current = next
+	}
+	flush()
+	return parts
+}
+
+// appendLine appends line to t on its own line. Appending an empty line leaves an empty line between
+// t and the next line appended.
+func appendLine(t Text, line Text) Text {
+	if len(t.Nodes) == 0 {
+		return line
+	}
+	nodes := slices.Clone(t.Nodes)
+	if !endsLine(nodes) {
+		nodes = append(nodes, &TextNode{Text: "\n"})
+	}
+	return Text{Nodes: append(nodes, line.Nodes...), source: t.source + "\n" + line.source}
+}
@@ -0,0 +1,92 @@
+package parserv5
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+	"sync"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+	"gopkg.in/telebot.v4"
+)
+
+// stickerFiles maps Discord sticker IDs to the Telegram file ID of their first upload,
+// so that repeated stickers are not downloaded and uploaded again.
+var stickerFiles sync.Map
+
+// StickerURL returns the CDN link of sticker, or an empty string for Lottie stickers
+// which Telegram cannot display.
+func StickerURL(sticker *discordgo.StickerItem) string {
+	switch sticker.FormatType {
+	case discordgo.StickerFormatTypePNG, discordgo.StickerFormatTypeAPNG:
+		return fmt.Sprintf("https://media.discordapp.net/stickers/%s.png", sticker.ID)
+	case discordgo.StickerFormatTypeGIF:
+		return fmt.Sprintf("https://media.discordapp.net/stickers/%s.gif", sticker.ID)
+	default:
+		return ""
+	}
+}
+
+// stickerSendable converts sticker into a Captioned *telebot.Photo, or *telebot.Animation for GIF stickers.
+// Lottie stickers fall back to their name appended to caption.
+func stickerSendable(sticker *discordgo.StickerItem, caption string, p parser) (any, error) {
+	url := StickerURL(sticker)
+	if url == "" {
+		name := fmt.Sprintf("_\\[Sticker: %s\\]_", lib.EscapeMarkdown(sticker.Name))
+		return p(strings.TrimSpace(caption + " " + name)), nil
+	}
+
+	file, err := stickerFile(sticker, url)
+	if err != nil {
+		return nil, err
+	}
+	if sticker.FormatType == discordgo.StickerFormatTypeGIF {
+		return Captioned{Media: &telebot.Animation{File: file, FileName: sticker.Name + ".gif"}, Caption: p(caption)}, nil
+	}
+	return Captioned{Media: &telebot.Photo{File: file}, Caption: p(caption)}, nil
+}
+
+// stickerFile returns the uploaded Telegram file of sticker if it was sent before,
+// or downloads it from url. APNG stickers are flattened to their first frame, as Telegram
+// only accepts static PNG photos.
+func stickerFile(sticker *discordgo.StickerItem, url string) (telebot.File, error) {
+	if id, ok := stickerFiles.Load(sticker.ID); ok {
+		return telebot.File{FileID: id.(string)}, nil
+	}
+	data, err := lib.DefaultCache.Get(url)
+	if err != nil {
+		return telebot.File{}, err
+	}
+	if sticker.FormatType == discordgo.StickerFormatTypeAPNG {
+		frame, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return telebot.File{}, fmt.Errorf("error decoding APNG sticker %s: %w", sticker.ID, err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, frame); err != nil {
+			return telebot.File{}, fmt.Errorf("error encoding APNG sticker %s: %w", sticker.ID, err)
+		}
+		data = buf.Bytes()
+	}
+	return telebot.FromReader(bytes.NewReader(data)), nil
+}
+
+// RememberSticker stores the Telegram file ID of the sticker in m once sent has been delivered.
+func RememberSticker(m *discordgo.Message, sent *telebot.Message) {
+	if m == nil || sent == nil || len(m.StickerItems) == 0 {
+		return
+	}
+	var id string
+	switch {
+	case sent.Photo != nil:
+		id = sent.Photo.FileID
+	case sent.Animation != nil:
+		id = sent.Animation.FileID
+	}
+	if id != "" {
+		stickerFiles.Store(m.StickerItems[0].ID, id)
+	}
+}
@@ -0,0 +1,117 @@
+package parserv5
+
+import (
+	"fmt"
+	"strings"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MessageTypeAutoModerationAction is not defined by discordgo.
+const MessageTypeAutoModerationAction discordgo.MessageType = 24
+
+// SystemTypes maps the configuration name of each renderable system message to its Discord message types.
+var SystemTypes = map[string][]discordgo.MessageType{
+	"recipient": {discordgo.MessageTypeRecipientAdd, discordgo.MessageTypeRecipientRemove},
+	"call":      {discordgo.MessageTypeCall},
+	"channel":   {discordgo.MessageTypeChannelNameChange, discordgo.MessageTypeChannelIconChange},
+	"pin":       {discordgo.MessageTypeChannelPinnedMessage},
+	"join":      {discordgo.MessageTypeGuildMemberJoin},
+	"boost": {
+		discordgo.MessageTypeUserPremiumGuildSubscription,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierOne,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierTwo,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierThree,
+	},
+	"follow":  {discordgo.MessageTypeChannelFollowAdd},
+	"thread":  {discordgo.MessageTypeThreadCreated},
+	"automod": {MessageTypeAutoModerationAction},
+}
+
+// ParseSystemTypes returns the enabled system message types from a comma separated list of SystemTypes names.
+// An empty list or "none" disables every type, so that system messages are opt-in, while "all" enables them all.
+func ParseSystemTypes(list string) map[discordgo.MessageType]bool {
+	enabled := make(map[discordgo.MessageType]bool)
+	list = strings.TrimSpace(list)
+	if list == "" || list == "none" {
+		return enabled
+	}
+	for _, types := range SystemTypes {
+		for _, t := range types {
+			enabled[t] = list == "all"
+		}
+	}
+	for name := range strings.SplitSeq(list, ",") {
+		for _, t := range SystemTypes[strings.TrimSpace(name)] {
+			enabled[t] = true
+		}
+	}
+	return enabled
+}
+
+// IsSystem reports whether m is a system message that SystemMessage can render.
+func IsSystem(m *discordgo.Message) bool {
+	_, ok := SystemMessage(m)
+	return ok
+}
+
+// SystemMessage renders a Discord system message, such as a pin or a member join, as a
+// Discord markdown sentence. It returns false if m is not a system message.
+func SystemMessage(m *discordgo.Message) (string, bool) {
+	author := "Someone"
+	if user := lib.GetUser(m); user != nil && user.DisplayName() != "" {
+		author = user.DisplayName()
+	}
+	author = fmt.Sprintf("**%s**", lib.EscapeMarkdown(author))
+	content := lib.EscapeMarkdown(m.Content)
+
+	switch m.Type {
+	case discordgo.MessageTypeRecipientAdd, discordgo.MessageTypeRecipientRemove:
+		recipient := "someone"
+		if len(m.Mentions) > 0 {
+			recipient = fmt.Sprintf("**%s**", lib.EscapeMarkdown(m.Mentions[0].DisplayName()))
+		}
+		if m.Type == discordgo.MessageTypeRecipientAdd {
+			return fmt.Sprintf("➕ %s added %s to the group", author, recipient), true
+		}
+		return fmt.Sprintf("➖ %s removed %s from the group", author, recipient), true
+	case discordgo.MessageTypeCall:
+		return fmt.Sprintf("📞 %s started a call", author), true
+	case discordgo.MessageTypeChannelNameChange:
+		return fmt.Sprintf("✏️ %s changed the channel name: **%s**", author, content), true
+	case discordgo.MessageTypeChannelIconChange:
+		return fmt.Sprintf("🖼️ %s changed the channel icon", author), true
+	case discordgo.MessageTypeChannelPinnedMessage:
+		return fmt.Sprintf("📌 %s pinned a message to this channel", author), true
+	case discordgo.MessageTypeGuildMemberJoin:
+		return fmt.Sprintf("👋 %s joined the server", author), true
+	case discordgo.MessageTypeUserPremiumGuildSubscription:
+		if m.Content != "" {
+			return fmt.Sprintf("🚀 %s boosted the server **%s** times", author, content), true
+		}
+		return fmt.Sprintf("🚀 %s boosted the server", author), true
+	case discordgo.MessageTypeUserPremiumGuildSubscriptionTierOne,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierTwo,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierThree:
+		level := int(m.Type-discordgo.MessageTypeUserPremiumGuildSubscriptionTierOne) + 1
+		return fmt.Sprintf("🚀 %s boosted the server, it has reached **Level %d**", author, level), true
+	case discordgo.MessageTypeChannelFollowAdd:
+		return fmt.Sprintf("🔔 %s followed **%s** in this channel", author, content), true
+	case discordgo.MessageTypeThreadCreated:
+		return fmt.Sprintf("🧵 New thread: **%s**, started by %s", content, author), true
+	case MessageTypeAutoModerationAction:
+		text := fmt.Sprintf("🛡️ AutoMod blocked a message from %s", author)
+		for _, embed := range m.Embeds {
+			for _, field := range embed.Fields {
+				if field.Name == "rule_name" {
+					text += fmt.Sprintf(" for breaking **%s**", lib.EscapeMarkdown(field.Value))
+				}
+			}
+		}
+		return text, true
+	default:
+		return "", false
+	}
+}
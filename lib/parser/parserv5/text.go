@@ -0,0 +1,159 @@
+package parserv5
+
+import (
+	"strings"
+	"unicode"
+
+	"telegram-discord/lib"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Text is formatted text kept as the AST it is rendered from until it is sent, so that it can be rendered
+// as MarkdownV2, HTML or entities by a Renderer. It also keeps the Discord markdown it was parsed from.
+type Text struct {
+	Nodes  []Node
+	source string
+}
+
+// ParseText parses the Discord markdown text of m into a Text. Its MarkdownV2 is the same as Parse.
+// Custom emoji are rendered by name, Parser renders them as configured.
+func ParseText(s *discordgo.Session, m *discordgo.Message, text string) Text {
+	return parseText(s, m, text, EmojiOptions{Mode: EmojiModeName})
+}
+
+func parseText(s *discordgo.Session, m *discordgo.Message, text string, emoji EmojiOptions) Text {
+	source := text
+	text = preprocess(s, m, text, emoji)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return Text{Nodes: trimNodes(buildAST(text)), source: source}
+}
+
+// Plain returns text as a Text without formatting.
+func Plain(text string) Text {
+	if text == "" {
+		return Text{}
+	}
+	return Text{Nodes: []Node{&TextNode{Text: text}}, source: lib.EscapeMarkdown(text)}
+}
+
+// String renders t as Telegram MarkdownV2.
+func (t Text) String() string {
+	return trimMarkdown(renderNodes(t.Nodes, 0))
+}
+
+// Source returns the Discord markdown t was parsed from.
+func (t Text) Source() string {
+	return t.source
+}
+
+// Len returns the length Telegram counts for t, as counted by TextLength.
+func (t Text) Len() int {
+	return TextLength(t.Nodes)
+}
+
+// IsEmpty reports whether t has no text to send.
+func (t Text) IsEmpty() bool {
+	return t.Len() == 0
+}
+
+// Join joins the texts that are not empty with sep, which is plain text.
+func Join(sep string, texts ...Text) Text {
+	var joined Text
+	for _, t := range texts {
+		if t.IsEmpty() {
+			continue
+		}
+		if len(joined.Nodes) > 0 {
+			separator := sep
+			// Quotes and headers end their own line.
+			if endsLine(joined.Nodes) {
+				separator = strings.TrimPrefix(separator, "\n")
+			}
+			if separator != "" {
+				joined.Nodes = append(joined.Nodes, &TextNode{Text: separator})
+			}
+			joined.source += sep
+		}
+		joined.Nodes = append(joined.Nodes, t.Nodes...)
+		joined.source += t.source
+	}
+	return joined
+}
+
+// format wraps t in the Discord formatting token.
+func (t Text) format(token string) Text {
+	if t.IsEmpty() {
+		return t
+	}
+	return Text{
+		Nodes:  []Node{&FormattingNode{Format: token, Children: t.Nodes}},
+		source: token + t.source + token,
+	}
+}
+
+// bold formats t in bold.
+func (t Text) bold() Text {
+	return t.format("**")
+}
+
+// italic formats t in italic.
+func (t Text) italic() Text {
+	return t.format("_")
+}
+
+// strike formats t with a strikethrough.
+func (t Text) strike() Text {
+	return t.format("~~")
+}
+
+// quote puts t in a quote block.
+func (t Text) quote() Text {
+	if t.IsEmpty() {
+		return t
+	}
+	return Text{Nodes: []Node{&QuoteBlockNode{Children: t.Nodes}}, source: "> " + t.source}
+}
+
+// link makes t a link to url, if there is one.
+func (t Text) link(url string) Text {
+	if url == "" || t.IsEmpty() {
+		return t
+	}
+	label, _ := Entities(t.Nodes)
+	return Text{
+		Nodes:  []Node{&LinkNode{Text: label, URL: url, Children: t.Nodes}},
+		source: "[" + t.source + "](" + url + ")",
+	}
+}
+
+// endsLine reports whether the last of nodes renders its own line break.
+func endsLine(nodes []Node) bool {
+	if len(nodes) == 0 {
+		return false
+	}
+	switch n := nodes[len(nodes)-1].(type) {
+	case *QuoteBlockNode:
+		return true
+	case *HeaderNode:
+		return n.Level <= 3
+	}
+	return false
+}
+
+// trimNodes removes the whitespace text nodes at both ends of nodes.
+func trimNodes(nodes []Node) []Node {
+	isSpace := func(node Node) bool {
+		n, ok := node.(*TextNode)
+		return ok && !n.Raw && strings.TrimFunc(n.Text, unicode.IsSpace) == ""
+	}
+	for len(nodes) > 0 && isSpace(nodes[0]) {
+		nodes = nodes[1:]
+	}
+	for len(nodes) > 0 && isSpace(nodes[len(nodes)-1]) {
+		nodes = nodes[:len(nodes)-1]
+	}
+	return nodes
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"telegram-discord/bot"
+	"telegram-discord/bot/telegram"
 	"telegram-discord/lib/parser"
 	"telegram-discord/lib/parser/parserv2"
 	"telegram-discord/lib/parser/parserv3"
@@ -15,6 +19,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
+	"gopkg.in/telebot.v4"
 )
 
 var useSession = os.Getenv("USE_SESSION") == "true"
@@ -421,11 +426,12 @@ __underline__
 
 // TestParseV5 tests parserv5.Parse,
 // which first converts Discord-specific constructs (like timestamps and mentions)
-// then calls the markdown parser.
+// then calls the markdown parser. Cases with an embed test how parserv5.Sendable renders it instead.
 func TestParseV5(t *testing.T) {
 	var tests = []struct {
 		name     string
 		input    string
+		embed    *discordgo.MessageEmbed
 		expected string
 	}{
 		{
@@ -498,6 +504,11 @@ func TestParseV5(t *testing.T) {
 			input:    "This is a channel <#1335581350731972648> testing",
 			expected: "This is a channel \\#🔔・𝙑𝙍\\-announcements testing",
 		},
+		{
+			name:     "Custom Emoji",
+			input:    "Hi <:wave:123456> and <a:party_blob:654321>",
+			expected: "Hi :wave: and :party\\_blob:",
+		},
 		{
 			name:     "URL",
 			input:    "This is [an example](http://www.example.com/) link.",
@@ -548,9 +559,58 @@ __underline__
 			input:    `<t:1743516000:t>`,
 			expected: "*2:00 PM UTC* \\(10:00 AM EDT\\)",
 		},
+		{
+			name: "Embed Title",
+			embed: &discordgo.MessageEmbed{
+				Title:       "Release v1.2",
+				URL:         "https://example.com/release",
+				Description: "Notes.",
+			},
+			expected: "*[Release v1\\.2](https://example.com/release)*\nNotes\\.",
+		},
+		{
+			name: "Embed Fields",
+			embed: &discordgo.MessageEmbed{
+				Description: "Stats",
+				Fields: []*discordgo.MessageEmbedField{
+					{Name: "A", Value: "1", Inline: true},
+					{Name: "B", Value: "2", Inline: true},
+					{Name: "C", Value: "3"},
+				},
+			},
+			expected: "Stats\n\n*A:* 1 │ *B:* 2\n*C:*\n3",
+		},
+		{
+			name: "Embed Footer",
+			embed: &discordgo.MessageEmbed{
+				Description: "Done",
+				Footer:      &discordgo.MessageEmbedFooter{Text: "Bot"},
+				Timestamp:   "2025-04-01T14:00:00Z",
+			},
+			expected: "Done\n\n_Bot_ • *April 01, 2025 2:00 PM UTC* \\(April 01, 2025 10:00 AM EDT\\)",
+		},
+		{
+			name: "Embed Author URL",
+			embed: &discordgo.MessageEmbed{
+				Author: &discordgo.MessageEmbedAuthor{Name: "Jane", URL: "https://example.com/jane"},
+				Title:  "Post",
+				Color:  0xdd2e44,
+			},
+			expected: "🟥 [Jane](https://example.com/jane)\n*Post*",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			if tt.embed != nil {
+				toSend, err := parserv5.Sendable(session, &discordgo.Message{Embeds: []*discordgo.MessageEmbed{tt.embed}}, nil, parserv5.Options{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := fmt.Sprint(toSend); got != tt.expected {
+					t.Errorf("Sendable(%+v) = %q; want %q", tt.embed, got, tt.expected)
+				}
+				return
+			}
 			got := parserv5.Parse(session, nil, tt.input)
 			if got != tt.expected {
 				t.Errorf("DiscordToTelegramMarkdown(%q) = %q; want %q", tt.input, got, tt.expected)
@@ -560,3 +620,554 @@ __underline__
 		})
 	}
 }
+
+// TestParsePartsV5 tests parserv5.SplitText, which splits long texts along the AST.
+func TestParsePartsV5(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "Paragraphs",
+			input:    "First paragraph.\n\nSecond paragraph.",
+			limit:    20,
+			expected: []string{"First paragraph\\.", "Second paragraph\\."},
+		},
+		{
+			name:     "Sentences",
+			input:    "First sentence. Second sentence.",
+			limit:    20,
+			expected: []string{"First sentence\\.", "Second sentence\\."},
+		},
+		{
+			name:     "Formatting",
+			input:    "**bold words that do not fit**",
+			limit:    15,
+			expected: []string{"*bold words *", "*that do not fit*"},
+		},
+		{
+			name:     "Code Block",
+			input:    "```go\nfirst()\nsecond()\n```",
+			limit:    12,
+			expected: []string{"```go\nfirst()\n```", "```go\nsecond()\n```"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, part := range parserv5.SplitText(parserv5.ParseText(session, nil, tt.input), tt.limit) {
+				got = append(got, part.String())
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("SplitText(%q, %d) = %q; want %q", tt.input, tt.limit, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSplitTextMarkdownV2 tests that every part of parserv5.SplitText renders to valid MarkdownV2 on its own,
+// whatever the limit it is split with.
+func TestSplitTextMarkdownV2(t *testing.T) {
+	var tests = []string{
+		"~~**||!**`!\\\n# ab cd[a](http://x.y)x😀*xword",
+		"ab cd-`\\word(**!.word__",
+		"**!word||😀x\n😀é__[a](http://x.y)~~\né`",
+		"ab cd*> > x(** **é😀é**.word\n\nword~~",
+		"*é~~😀 **ab cd\n\n||__\n\n```word\n(!**```",
+		"😀[a](http://x.y)\n\n-ab cd__x`*> \\\n**__x[a](http://x.y)```ab cd😀.",
+		"_😀(é!\n\n> > > .```-\n._> ```",
+		"# > ..*\n\n||_-😀# word-x.*",
+		"_italic __underlined___ and *more **bold** italic* text",
+		"> quoted **bold\n> text** end\n\n||spoiler with a longer line of words||",
+	}
+	for _, input := range tests {
+		whole := parserv5.ParseText(session, nil, input)
+		if err := checkMarkdownV2(whole.String()); err != nil {
+			t.Errorf("ParseText(%q) = %q: %v", input, whole.String(), err)
+			continue
+		}
+		for limit := 1; limit <= whole.Len(); limit++ {
+			for _, part := range parserv5.SplitText(whole, limit) {
+				if err := checkMarkdownV2(part.String()); err != nil {
+					t.Errorf("SplitText(%q, %d) part %q: %v", input, limit, part.String(), err)
+				}
+			}
+		}
+	}
+}
+
+// TestQuoteV5 tests parserv5.Quote, which cuts the excerpt of the quoted message between the nodes of its AST.
+func TestQuoteV5(t *testing.T) {
+	var tests = []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Short",
+			content:  "short *one*\nsecond line",
+			expected: ">*Ann*: short _one_",
+		},
+		{
+			name:     "Link at the cut",
+			content:  strings.Repeat("word ", 19) + "[a long link label](https://example.com) end",
+			expected: ">*Ann*: " + strings.TrimSpace(strings.Repeat("word ", 19)) + "…",
+		},
+		{
+			name:     "Formatting at the cut",
+			content:  strings.Repeat("word ", 19) + "**bold words here** end",
+			expected: ">*Ann*: " + strings.Repeat("word ", 19) + "*bold*…",
+		},
+		{
+			name:     "Custom emoji at the cut",
+			content:  strings.Repeat("word ", 18) + "wor <:party_parrot:123> end",
+			expected: ">*Ann*: " + strings.Repeat("word ", 18) + "wor…",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			referenced := &discordgo.Message{Content: tt.content, Author: &discordgo.User{Username: "ann", GlobalName: "Ann"}}
+			got := parserv5.Quote(session, referenced, parserv5.Options{}).String()
+			if got != tt.expected {
+				t.Errorf("Quote(%q) = %q; want %q", tt.content, got, tt.expected)
+			}
+			if err := checkMarkdownV2(got); err != nil {
+				t.Errorf("Quote(%q) = %q: %v", tt.content, got, err)
+			}
+		})
+	}
+}
+
+// TestWithPrefixV5 tests parserv5.WithPrefix, which keeps a reply quote or forward attribution on every kind
+// of message, including native polls that have no caption.
+func TestWithPrefixV5(t *testing.T) {
+	prefix := parserv5.ParseText(session, nil, "> **Ann**: hello")
+	poll := parserv5.NativePoll(&discordgo.Poll{
+		Question: discordgo.PollMedia{Text: "Lunch?"},
+		Answers: []discordgo.PollAnswer{
+			{Media: &discordgo.PollMedia{Text: "Yes"}},
+			{Media: &discordgo.PollMedia{Text: "No"}},
+		},
+	})
+	var tests = []struct {
+		name     string
+		toSend   any
+		expected string
+	}{
+		{
+			name:     "Text",
+			toSend:   parserv5.ParseText(session, nil, "reply"),
+			expected: ">*Ann*: hello\nreply",
+		},
+		{
+			name:     "Poll",
+			toSend:   poll,
+			expected: "Ann: hello\nLunch?",
+		},
+		{
+			name:     "Long poll question",
+			toSend:   &telebot.Poll{Question: strings.Repeat("a", 300)},
+			expected: "Ann: hello\n" + strings.Repeat("a", 300-len("Ann: hello\n")-1) + "…",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			switch v := parserv5.WithPrefix(tt.toSend, prefix).(type) {
+			case parserv5.Text:
+				got = v.String()
+			case *telebot.Poll:
+				got = v.Question
+			default:
+				t.Fatalf("WithPrefix(%T) = %T", tt.toSend, v)
+			}
+			if got != tt.expected {
+				t.Errorf("WithPrefix(%T) = %q; want %q", tt.toSend, got, tt.expected)
+			}
+		})
+	}
+	if poll.Question != "Lunch?" {
+		t.Errorf("WithPrefix changed the question of the original poll to %q", poll.Question)
+	}
+}
+
+// TestTextLengthV5 tests parserv5.TextLength, which counts text the way Telegram does:
+// in UTF-16 code units, without markup and surrounding whitespace.
+func TestTextLengthV5(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{name: "ASCII", input: "hello", expected: 5},
+		{name: "Escaped Characters", input: "a.b!(c)", expected: 7},
+		{name: "Markup", input: "**bold** `code` [link](https://example.com)", expected: 14},
+		{name: "Surrogate Pairs", input: "😀 𝄞", expected: 5},
+		{name: "BMP Characters", input: "日本語 é", expected: 5},
+		{name: "Code Block", input: "```go\nx := 1\n```", expected: 6},
+		{name: "Surrounding Whitespace", input: "\n  a  \n", expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parserv5.TextLength(parserv5.ParseText(session, nil, tt.input).Nodes)
+			if got != tt.expected {
+				t.Errorf("TextLength(%q) = %d; want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestReconcilePartsV5 tests parserv5.ReconcileParts, which edits, sends and removes the messages of text parts
+// when a split text is edited into more or fewer parts.
+func TestReconcilePartsV5(t *testing.T) {
+	var tests = []struct {
+		name     string
+		previous []string
+		parts    []string
+		fail     string
+		expected []string
+		removed  []string
+		err      bool
+	}{
+		{
+			name:     "Same Parts",
+			previous: []string{"1", "2"},
+			parts:    []string{"a", "b"},
+			expected: []string{"1=a", "2=b"},
+		},
+		{
+			name:     "Growing",
+			previous: []string{"1"},
+			parts:    []string{"a", "b", "c"},
+			expected: []string{"1=a", "new=b", "new=c"},
+		},
+		{
+			name:     "Shrinking",
+			previous: []string{"1", "2", "3"},
+			parts:    []string{"a"},
+			expected: []string{"1=a"},
+			removed:  []string{"2", "3"},
+		},
+		{
+			name:     "All Removed",
+			previous: []string{"1", "2"},
+			removed:  []string{"1", "2"},
+		},
+		{
+			name:     "Failed Edit",
+			previous: []string{"1", "2"},
+			parts:    []string{"a", "b"},
+			fail:     "a",
+			expected: []string{"1", "2=b"},
+			err:      true,
+		},
+		{
+			name:     "Failed Send",
+			previous: []string{"1"},
+			parts:    []string{"a", "b", "c"},
+			fail:     "b",
+			expected: []string{"1=a", "new=c"},
+			err:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := make([]parserv5.Text, len(tt.parts))
+			for i, part := range tt.parts {
+				parts[i] = parserv5.Plain(part)
+			}
+			edit := func(message string, part parserv5.Text) (string, error) {
+				if part.String() == tt.fail {
+					return "", fmt.Errorf("error editing %s", message)
+				}
+				return message + "=" + part.String(), nil
+			}
+			send := func(part parserv5.Text) (string, error) {
+				if part.String() == tt.fail {
+					return "", fmt.Errorf("error sending %s", part)
+				}
+				return "new=" + part.String(), nil
+			}
+			var removed []string
+			remove := func(messages []string) {
+				removed = append(removed, messages...)
+			}
+
+			got, err := parserv5.ReconcileParts(tt.previous, parts, edit, send, remove)
+			if !slices.Equal(got, tt.expected) || !slices.Equal(removed, tt.removed) || (err != nil) != tt.err {
+				t.Errorf(
+					"ReconcileParts(%q, %q) = %q, removed %q, error %v; want %q, removed %q, error %t",
+					tt.previous, tt.parts, got, removed, err, tt.expected, tt.removed, tt.err,
+				)
+			}
+		})
+	}
+}
+
+// TestEntitiesV5 tests parserv5.Entities, which renders the AST of parserv5.ParseText as plain text and entities.
+func TestEntitiesV5(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		text     string
+		entities telebot.Entities
+	}{
+		{
+			name:  "Nested Formatting",
+			input: "**bold _italic_**",
+			text:  "bold italic",
+			entities: telebot.Entities{
+				{Type: telebot.EntityBold, Offset: 0, Length: 11},
+				{Type: telebot.EntityItalic, Offset: 5, Length: 6},
+			},
+		},
+		{
+			name:  "UTF-16 Offsets",
+			input: "😀 ||spoiler|| [link](https://example.com)",
+			text:  "😀 spoiler link",
+			entities: telebot.Entities{
+				{Type: telebot.EntitySpoiler, Offset: 3, Length: 7},
+				{Type: telebot.EntityTextLink, Offset: 11, Length: 4, URL: "https://example.com"},
+			},
+		},
+		{
+			name:  "Escapes and Code",
+			input: "1. `a*b`\n```go\nx := 1\n```",
+			text:  "1. a*b\nx := 1",
+			entities: telebot.Entities{
+				{Type: telebot.EntityCode, Offset: 3, Length: 3},
+				{Type: telebot.EntityCodeBlock, Offset: 7, Length: 6, Language: "go"},
+			},
+		},
+		{
+			name:  "Headers",
+			input: "# Title\n### Section\ntext",
+			text:  "Title\nSection\ntext",
+			entities: telebot.Entities{
+				{Type: telebot.EntityBold, Offset: 0, Length: 5},
+				{Type: telebot.EntityBlockquote, Offset: 0, Length: 5},
+				{Type: telebot.EntityBold, Offset: 6, Length: 7},
+			},
+		},
+		{
+			name:  "Emoji and Timestamp",
+			input: "<:wave:123> <t:0:d>",
+			text:  ":wave: 01/01/1970 (31/12/1969)",
+			entities: telebot.Entities{
+				{Type: telebot.EntityBold, Offset: 7, Length: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities := parserv5.Entities(parserv5.ParseText(session, nil, tt.input).Nodes)
+			if text != tt.text || !reflect.DeepEqual(entities, tt.entities) {
+				t.Errorf("Entities(%q) = %q, %+v; want %q, %+v", tt.input, text, entities, tt.text, tt.entities)
+			}
+		})
+	}
+}
+
+// TestHTMLV5 tests parserv5.HTML, which renders the AST of parserv5.ParseText as Telegram HTML.
+func TestHTMLV5(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Formatting",
+			input:    "**bold** *italic* __underline__ ~~strike~~ ||spoiler||",
+			expected: "<b>bold</b> <i>italic</i> <u>underline</u> <s>strike</s> <tg-spoiler>spoiler</tg-spoiler>",
+		},
+		{
+			name:     "Escaping",
+			input:    "a < b & [c > d](https://example.com/?a=1&b=2) `<tag>`",
+			expected: `a &lt; b &amp; <a href="https://example.com/?a=1&amp;b=2">c &gt; d</a> <code>&lt;tag&gt;</code>`,
+		},
+		{
+			name:     "Code Block",
+			input:    "```go\nx := 1\n```",
+			expected: "<pre><code class=\"language-go\">x := 1\n</code></pre>",
+		},
+		{
+			name:     "Expandable Quote",
+			input:    "> one\n> two\n> three\n> four\n",
+			expected: "<blockquote expandable>one\ntwo\nthree\nfour</blockquote>",
+		},
+		{
+			name:     "Headers and Links",
+			input:    "# Title\n[link](https://example.com)",
+			expected: "<blockquote><b>Title</b></blockquote>\n<a href=\"https://example.com\">link</a>",
+		},
+		{
+			name:     "Emoji and Timestamp",
+			input:    "<:wave:123> <t:0:d>",
+			expected: ":wave: <b>01/01/1970</b> (31/12/1969)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parserv5.HTML(parserv5.ParseText(session, nil, tt.input).Nodes)
+			if got != tt.expected {
+				t.Errorf("HTML(%q) = %q; want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMarkupSamples checks that the Discord markdown of the samples Telegram rejected, saved to ./samples
+// by the bot when MARKUP_SAMPLES_DIR points there, is now rendered by parserv5 into well-formed MarkdownV2.
+func TestMarkupSamples(t *testing.T) {
+	paths, err := filepath.Glob("./samples/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sample telegram.MarkupSample
+			if err := json.Unmarshal(data, &sample); err != nil {
+				t.Fatal(err)
+			}
+			if sample.Source == "" {
+				t.Skip("sample has no source")
+			}
+			markup := parserv5.ParseText(session, nil, sample.Source).String()
+			if err := checkMarkdownV2(markup); err != nil {
+				t.Errorf("ParseText(%q) = %q: %v (rejected with %q)", sample.Source, markup, err, sample.Error)
+			}
+		})
+	}
+}
+
+// TestCheckMarkdownV2 tests checkMarkdownV2 against MarkdownV2 Telegram accepts and rejects.
+func TestCheckMarkdownV2(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{name: "Escaped", input: "a\\.b \\(c\\)", valid: true},
+		{name: "Nested", input: "*bold _italic ~strike~_* __underline__ ||spoiler||", valid: true},
+		{name: "Link", input: "[label \\[1\\]](https://example.com/a_\\(b\\))", valid: true},
+		{name: "Code", input: "`a*b\\`` ```go\nx := \"_\"\n```", valid: true},
+		{name: "Quote", input: ">quote\n>lines\ntext", valid: true},
+		{name: "Unescaped", input: "a.b", valid: false},
+		{name: "Unclosed", input: "*bold", valid: false},
+		{name: "Crossed", input: "*bold _italic* end_", valid: false},
+		{name: "Link Without URL", input: "[label]", valid: false},
+		{name: "Unescaped In Code", input: "`a\\b`", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkMarkdownV2(tt.input); (err == nil) != tt.valid {
+				t.Errorf("checkMarkdownV2(%q) = %v; want valid %t", tt.input, err, tt.valid)
+			}
+		})
+	}
+}
+
+// checkMarkdownV2 reports the first reason Telegram would reject text as MarkdownV2: a reserved character
+// that is not escaped, or an entity that is not closed or not properly nested.
+func checkMarkdownV2(text string) error {
+	var open []string
+	top := func() string {
+		if len(open) == 0 {
+			return ""
+		}
+		return open[len(open)-1]
+	}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(text) || text[i+1] < 1 || text[i+1] > 126 {
+				return fmt.Errorf("invalid escape at byte offset %d", i)
+			}
+			i++
+		case c == '`':
+			token := "`"
+			if strings.HasPrefix(text[i:], "```") {
+				token = "```"
+			}
+			end, err := codeEnd(text, i+len(token), token)
+			if err != nil {
+				return err
+			}
+			i = end + len(token) - 1
+		case c == '>' && (i == 0 || text[i-1] == '\n'):
+		case c == '[':
+			open = append(open, "[")
+		case c == ']':
+			if top() != "[" {
+				return fmt.Errorf("unexpected ] at byte offset %d", i)
+			}
+			open = open[:len(open)-1]
+			if i+1 >= len(text) || text[i+1] != '(' {
+				return fmt.Errorf("link without URL at byte offset %d", i)
+			}
+			end, err := urlEnd(text, i+2)
+			if err != nil {
+				return err
+			}
+			i = end
+		case strings.ContainsRune("*~_|", rune(c)):
+			token := string(c)
+			if c == '|' || (c == '_' && strings.HasPrefix(text[i:], "__")) {
+				token = text[i:min(i+2, len(text))]
+			}
+			if token == "|" {
+				return fmt.Errorf("character | is reserved at byte offset %d", i)
+			}
+			if top() == token {
+				open = open[:len(open)-1]
+			} else if slices.Contains(open, token) {
+				return fmt.Errorf("entity %s closed across another at byte offset %d", token, i)
+			} else {
+				open = append(open, token)
+			}
+			i += len(token) - 1
+		case strings.ContainsRune("()>#+-={}.!", rune(c)):
+			return fmt.Errorf("character %c is reserved at byte offset %d", c, i)
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("entity %s is not closed", top())
+	}
+	return nil
+}
+
+// codeEnd returns the offset of the token closing the code starting at start, in which only ` and \\ are escaped.
+func codeEnd(text string, start int, token string) (int, error) {
+	for i := start; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			if i+1 >= len(text) || (text[i+1] != '`' && text[i+1] != '\\') {
+				return 0, fmt.Errorf("invalid escape in code at byte offset %d", i)
+			}
+			i++
+		case strings.HasPrefix(text[i:], token):
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("code starting at byte offset %d is not closed", start-len(token))
+}
+
+// urlEnd returns the offset of the ) closing the link URL starting at start, in which only ) and \\ are escaped.
+func urlEnd(text string, start int) (int, error) {
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case ')':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("link URL starting at byte offset %d is not closed", start)
+}
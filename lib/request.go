@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"telegram-discord/lib/flight"
@@ -31,3 +33,39 @@ func RetrieveFile(url string) ([]byte, error) {
 
 	return file.Bytes(), nil
 }
+
+// DownloadFile streams the file at url to path without holding it in memory, creating its directory if needed.
+// The file is written to a temporary file renamed to path once complete, so that path never holds a partial download.
+func DownloadFile(url string, path string) error {
+	client := &http.Client{Timeout: 30 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating download directory: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.part")
+	if err != nil {
+		return fmt.Errorf("error creating download file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing download file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing download file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("error moving download file: %w", err)
+	}
+	return nil
+}
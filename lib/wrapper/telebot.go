@@ -54,18 +54,116 @@ func (s String) Send(b *telebot.Bot, to telebot.Recipient, opt *telebot.SendOpti
 	return extractMessage(data)
 }
 
+// Preview is a text message whose link preview is generated from a chosen URL,
+// using link_preview_options which telebot.SendOptions does not support.
+type Preview struct {
+	Text    string
+	Options telebot.PreviewOptions
+}
+
+func (p Preview) Send(b *telebot.Bot, to telebot.Recipient, opt *telebot.SendOptions) (*telebot.Message, error) {
+	params := map[string]string{
+		"chat_id": to.Recipient(),
+		"text":    p.Text,
+	}
+	embedSendOptions(params, opt)
+	embedPreviewOptions(params, p.Options)
+
+	data, err := b.Raw("sendMessage", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractMessage(data)
+}
+
+// EditPreview edits the text and link preview of message.
+func EditPreview(b *telebot.Bot, message *telebot.Message, p Preview, opt *telebot.SendOptions) (*telebot.Message, error) {
+	msgID, chatID := message.MessageSig()
+	params := map[string]string{
+		"chat_id":    strconv.FormatInt(chatID, 10),
+		"message_id": msgID,
+		"text":       p.Text,
+	}
+	embedSendOptions(params, opt)
+	delete(params, "reply_to_message_id")
+	delete(params, "message_thread_id")
+	embedPreviewOptions(params, p.Options)
+
+	data, err := b.Raw("editMessageText", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractMessage(data)
+}
+
+// Captioned is an album item with its own caption entities, as telebot applies the entities
+// of the send options to every item of an album.
+type Captioned struct {
+	telebot.Inputtable
+	Entities telebot.Entities
+}
+
+func (c Captioned) InputMedia() telebot.InputMedia {
+	media := c.Inputtable.InputMedia()
+	media.Entities = c.Entities
+	return media
+}
+
+// Send sends the item on its own, for albums of a single item.
+func (c Captioned) Send(b *telebot.Bot, to telebot.Recipient, opt *telebot.SendOptions) (*telebot.Message, error) {
+	sendable, ok := c.Inputtable.(telebot.Sendable)
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be sent on its own", c.MediaType())
+	}
+	options := telebot.SendOptions{}
+	if opt != nil {
+		options = *opt
+	}
+	options.Entities = c.Entities
+	return sendable.Send(b, to, &options)
+}
+
+func embedPreviewOptions(params map[string]string, options telebot.PreviewOptions) {
+	delete(params, "disable_web_page_preview")
+	preview, _ := json.Marshal(options)
+	params["link_preview_options"] = string(preview)
+}
+
 func GetParsed(v any) string {
 	switch sendable := v.(type) {
+	case telebot.Album:
+		for _, item := range sendable {
+			if caption := GetParsed(item); caption != "" {
+				return caption
+			}
+		}
+		return ""
 	case *telebot.Photo:
 		return sendable.Caption
 	case *telebot.Document:
 		return sendable.Caption
+	case *telebot.Video:
+		return sendable.Caption
+	case *telebot.Animation:
+		return sendable.Caption
+	case *telebot.Audio:
+		return sendable.Caption
+	case *telebot.Voice:
+		return sendable.Caption
 	case *telebot.Poll:
 		return sendable.Question
+	case Captioned:
+		return GetParsed(sendable.Inputtable)
+	case Preview:
+		return sendable.Text
 	case String:
 		return string(sendable)
 	case string:
 		return sendable
+	case fmt.Stringer:
+		return sendable.String()
 	default:
 		return ""
 	}